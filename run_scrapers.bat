@@ -1,5 +1,5 @@
 @echo off
 echo Starting Job Scrapers...
 cd scrapers
-go run cmd\scraper\main.go
+go run .\cmd\scraper
 pause
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/digest"
+	"github.com/groot34/job-aggregator/scraper/internal/store"
+)
+
+// runDigestCommand handles `scraper digest` and returns the exit code
+func runDigestCommand(args []string) int {
+	defaultSubs := os.Getenv("DIGESTS_PATH")
+	if defaultSubs == "" {
+		defaultSubs = "digests.json"
+	}
+
+	fs := flag.NewFlagSet("digest", flag.ContinueOnError)
+	period := fs.String("period", "daily", "which subscriptions to send: daily or weekly")
+	subsPath := fs.String("subscriptions", defaultSubs, "path to the digest subscriptions file")
+	dbPath := fs.String("db", storePath(), "path to the job store")
+	dryRun := fs.Bool("dry-run", false, "print the emails instead of sending them")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	window, err := digest.Window(*period)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	subs, err := digest.LoadSubscriptions(*subsPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	searches, err := loadSearches()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	db, err := store.OpenReadOnly(*dbPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer db.Close()
+
+	since := time.Now().Add(-window)
+	newJobs, err := db.Query(store.Filter{SeenSince: since})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	cfg := digest.SMTPConfigFromEnv()
+	failed := false
+	for _, sub := range subs {
+		if sub.Period != *period {
+			continue
+		}
+		d, ok, err := digest.Build(sub, searches, newJobs, since)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			failed = true
+			continue
+		}
+		if !ok {
+			fmt.Printf("📭 Nothing new for %s\n", sub.To)
+			continue
+		}
+
+		if *dryRun {
+			msg, err := digest.Message(cfg.From, d)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				failed = true
+				continue
+			}
+			os.Stdout.Write(msg)
+			fmt.Println()
+			continue
+		}
+		if err := digest.Send(cfg, d); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			failed = true
+			continue
+		}
+		fmt.Printf("📧 Sent %s digest with %d jobs to %s\n", *period, d.Total, sub.To)
+	}
+
+	if failed {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+	"github.com/groot34/job-aggregator/scraper/internal/parsers"
+)
+
+// runExtractCommand prints the schema.org job postings found on each URL,
+// which is handy when checking whether a careers page can be scraped as-is
+func runExtractCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: scraper extract <url>...")
+		return 2
+	}
+
+	// Scrape progress goes to stderr so stdout stays valid JSON
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+
+	p := &parsers.JobPostingParser{}
+	jobs := []models.Job{}
+	for _, u := range args {
+		found, err := p.Parse(u)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		jobs = append(jobs, found...)
+	}
+	os.Stdout = stdout
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(jobs); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
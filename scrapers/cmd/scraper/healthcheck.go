@@ -0,0 +1,189 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/browser"
+	"github.com/groot34/job-aggregator/scraper/internal/health"
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+	"github.com/groot34/job-aggregator/scraper/internal/parsers"
+)
+
+// healthMonitor measures every scrape against parser expectations and the rolling baseline
+type healthMonitor struct {
+	baseline  *health.Baseline
+	metrics   map[string]health.Metrics
+	failed    map[string]bool // parsers that returned an error rather than results
+	anomalies []health.Anomaly
+	now       time.Time
+}
+
+func newHealthMonitor() *healthMonitor {
+	path := os.Getenv("HEALTH_BASELINE_PATH")
+	if path == "" {
+		path = "data/health.json"
+	}
+
+	baseline, err := health.LoadBaseline(path)
+	if err != nil {
+		log.Printf("⚠️  %v; starting a fresh baseline\n", err)
+		baseline = health.NewBaseline(path)
+	}
+	return &healthMonitor{
+		baseline: baseline,
+		metrics:  make(map[string]health.Metrics),
+		failed:   make(map[string]bool),
+		now:      time.Now(),
+	}
+}
+
+// observe checks one parser's raw output; a failed parser counts as zero jobs,
+// one with some failed boards as what the other boards returned
+func (h *healthMonitor) observe(res scrapeResult) {
+	m := res.metrics
+	var partial *parsers.BoardsError
+	if res.err != nil && !errors.As(res.err, &partial) {
+		m = health.Measure(nil)
+		h.failed[res.source] = true
+	}
+	h.metrics[res.source] = m
+
+	var exp health.Expectations
+	if hc, ok := res.parser.(parsers.HealthChecker); ok {
+		exp = hc.Expectations()
+	}
+	found := health.Check(res.source, m, exp, h.baseline.Metrics(res.source))
+	for _, a := range found {
+		fmt.Printf("🩺 %s\n", a)
+	}
+	h.anomalies = append(h.anomalies, found...)
+}
+
+// finish records this run into the baseline. Sources that returned nothing or
+// raised anomalies are held apart so one broken run can't drag the baseline
+// down; only when it keeps happening is the new level taken as normal. Parsers
+// that failed outright aren't recorded at all.
+func (h *healthMonitor) finish() {
+	unhealthy := make(map[string]bool)
+	for _, a := range h.anomalies {
+		unhealthy[a.Source] = true
+	}
+	for source, m := range h.metrics {
+		switch {
+		case h.failed[source]:
+		case m.Jobs == 0 || unhealthy[source]:
+			if h.baseline.Hold(source, m, h.now) {
+				fmt.Printf("🩺 %s has looked like this for several runs; it is the new baseline\n", source)
+			}
+		default:
+			h.baseline.Record(source, m, h.now)
+		}
+	}
+	if err := h.baseline.Save(); err != nil {
+		log.Printf("❌ Failed to save health baseline: %v\n", err)
+	}
+}
+
+// accept makes this run the baseline of each source, for a change that is
+// known to be real (a board that shrank, a field a site stopped showing), and
+// forgets the anomalies it raised
+func (h *healthMonitor) accept(sources []string) {
+	accepted := make(map[string]bool, len(sources))
+	for _, source := range sources {
+		m, ok := h.metrics[source]
+		if !ok || h.failed[source] {
+			fmt.Printf("⚠️  Not accepting %s: it didn't return results this run\n", source)
+			continue
+		}
+		h.baseline.Reset(source, m, h.now)
+		accepted[source] = true
+		fmt.Printf("🩺 Accepted %s at %d jobs as its new baseline\n", source, m.Jobs)
+	}
+
+	var kept []health.Anomaly
+	for _, a := range h.anomalies {
+		if !accepted[a.Source] {
+			kept = append(kept, a)
+		}
+	}
+	h.anomalies = kept
+
+	if err := h.baseline.Save(); err != nil {
+		log.Printf("❌ Failed to save health baseline: %v\n", err)
+	}
+}
+
+// runHealthcheckCommand scrapes every source without publishing and exits
+// non-zero when any parser looks broken
+func runHealthcheckCommand(args []string) int {
+	fs := flag.NewFlagSet("healthcheck", flag.ContinueOnError)
+	record := fs.Bool("record", true, "add this run to the rolling baseline")
+	only := fs.String("sources", "", "comma-separated sources to check, by name or ID prefix (default all)")
+	acceptList := fs.String("accept", "", "comma-separated sources whose results this run become their new baseline")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	siteParsers, err := parsers.Select(*only)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 2
+	}
+	var accept []string
+	for _, name := range strings.Split(*acceptList, ",") {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		info, ok := parsers.Lookup(name)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "❌ unknown source %q (see `scraper sources list`)\n", strings.TrimSpace(name))
+			return 2
+		}
+		accept = append(accept, info.Name)
+	}
+
+	defer browser.Shutdown()
+
+	monitor := newHealthMonitor()
+	for res := range scrapeAll(siteParsers, func(models.Job) {}) {
+		if res.err != nil {
+			fmt.Printf("❌ %s failed: %v\n", res.source, res.err)
+		}
+		monitor.observe(res)
+	}
+
+	sources := make([]string, 0, len(monitor.metrics))
+	for s := range monitor.metrics {
+		sources = append(sources, s)
+	}
+	sort.Strings(sources)
+
+	fmt.Println("\n🩺 Parser health")
+	for _, s := range sources {
+		m := monitor.metrics[s]
+		fmt.Printf("   %-14s %4d jobs", s, m.Jobs)
+		for _, f := range health.Fields {
+			fmt.Printf("  %s %3.0f%%", f, m.FillRates[f]*100)
+		}
+		fmt.Println()
+	}
+
+	if *record {
+		monitor.finish()
+	}
+	if len(accept) > 0 {
+		monitor.accept(accept)
+	}
+	if len(monitor.anomalies) > 0 {
+		fmt.Printf("\n❌ %d anomalies\n", len(monitor.anomalies))
+		return 1
+	}
+	fmt.Println("\n✅ All parsers healthy")
+	return 0
+}
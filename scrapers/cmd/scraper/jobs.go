@@ -0,0 +1,317 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/lifecycle"
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+	"github.com/groot34/job-aggregator/scraper/internal/store"
+)
+
+// runJobsCommand handles `scraper jobs <subcommand>` and returns the exit code
+func runJobsCommand(args []string) int {
+	if len(args) > 0 {
+		switch args[0] {
+		case "query":
+			return runJobsQuery(args[1:])
+		case "stats":
+			return runJobsStats(args[1:])
+		case "history":
+			return runJobsHistory(args[1:])
+		}
+	}
+	fmt.Fprintln(os.Stderr, "usage: scraper jobs query|stats [flags] | jobs history [flags] <id>")
+	return 2
+}
+
+// runJobsStats prints how long postings stay listed, from the lifecycle state
+func runJobsStats(args []string) int {
+	fs := flag.NewFlagSet("jobs stats", flag.ContinueOnError)
+	statePath := fs.String("state", lifecycle.Path(), "path to the lifecycle state")
+	by := fs.String("by", "source", "group by source or company")
+	format := fs.String("format", "table", "output format: table or json")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	tracker, err := lifecycle.Load(*statePath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	var stats []lifecycle.Stat
+	switch *by {
+	case "source":
+		stats = tracker.StatsBySource()
+	case "company":
+		stats = tracker.StatsByCompany()
+	default:
+		fmt.Fprintf(os.Stderr, "unknown --by %q (want source or company)\n", *by)
+		return 2
+	}
+
+	switch *format {
+	case "table":
+		err = writeStatsTable(os.Stdout, *by, stats)
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(stats)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown format %q\n", *format)
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func writeStatsTable(w io.Writer, by string, stats []lifecycle.Stat) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "%s\tOPEN\tCLOSED\tAVG FILL\tMEDIAN FILL\tMAX FILL\n", strings.ToUpper(by))
+	for _, s := range stats {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\t%s\n", truncate(s.Key, 30), s.Open, s.Closed,
+			fillTime(s.AvgTimeToFill, s.Closed), fillTime(s.MedianTimeToFill, s.Closed), fillTime(s.MaxTimeToFill, s.Closed))
+	}
+	return tw.Flush()
+}
+
+// fillTime renders a time-to-fill in days, or "-" when no job has closed yet
+func fillTime(d time.Duration, closed int) string {
+	if closed == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1fd", d.Hours()/24)
+}
+
+func runJobsQuery(args []string) int {
+	fs := flag.NewFlagSet("jobs query", flag.ContinueOnError)
+	dbPath := fs.String("db", storePath(), "path to the job store")
+	skill := fs.String("skill", "", "only jobs tagged with this skill")
+	source := fs.String("source", "", "only jobs from this source")
+	location := fs.String("location", "", "location substring")
+	remote := fs.String("remote", "", "true or false to filter on remote")
+	minSalary := fs.Int("min-salary", 0, "minimum salary (upper end of the posted range)")
+	since := fs.String("since", "", "posted on/after: YYYY-MM-DD or a duration like 7d, 36h")
+	until := fs.String("until", "", "posted before: YYYY-MM-DD or a duration like 7d, 36h")
+	sort := fs.String("sort", "-posted", "sort key (posted, scraped, company, title, salary), '-' prefix for descending")
+	limit := fs.Int("limit", 50, "maximum number of jobs (0 for all)")
+	format := fs.String("format", "table", "output format: table, json or csv")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	filter := store.Filter{
+		Skill:     *skill,
+		Source:    *source,
+		Location:  *location,
+		MinSalary: *minSalary,
+		Sort:      *sort,
+		Limit:     *limit,
+	}
+
+	var err error
+	if *remote != "" {
+		r, err := strconv.ParseBool(*remote)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid --remote value %q\n", *remote)
+			return 2
+		}
+		filter.Remote = &r
+	}
+	if filter.Since, err = parseDateFlag(*since); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid --since: %v\n", err)
+		return 2
+	}
+	if filter.Until, err = parseDateFlag(*until); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid --until: %v\n", err)
+		return 2
+	}
+
+	db, err := store.OpenReadOnly(*dbPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer db.Close()
+
+	jobs, err := db.Query(filter)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	switch *format {
+	case "table":
+		err = writeJobsTable(os.Stdout, jobs)
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(jobs)
+	case "csv":
+		err = writeJobsCSV(os.Stdout, jobs)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown format %q\n", *format)
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// runJobsHistory prints every recorded version of one job and what changed in each
+func runJobsHistory(args []string) int {
+	fs := flag.NewFlagSet("jobs history", flag.ContinueOnError)
+	dbPath := fs.String("db", storePath(), "path to the job store")
+	format := fs.String("format", "table", "output format: table or json")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: scraper jobs history [--db=path] [--format=table|json] <id>")
+		return 2
+	}
+	id := fs.Arg(0)
+
+	db, err := store.OpenReadOnly(*dbPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer db.Close()
+
+	entries, err := db.History(id)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if len(entries) == 0 {
+		fmt.Fprintf(os.Stderr, "no history for job %q\n", id)
+		return 1
+	}
+
+	switch *format {
+	case "table":
+		err = writeHistoryTable(os.Stdout, entries)
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(entries)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown format %q\n", *format)
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func writeHistoryTable(w io.Writer, entries []store.HistoryEntry) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RECORDED\tTITLE\tCOMPANY\tSALARY\tCHANGED")
+	for i, e := range entries {
+		changed := "first seen"
+		if i > 0 {
+			changed = strings.Join(changedFields(entries[i-1].Job, e.Job), ",")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", e.RecordedAt.Format("2006-01-02 15:04"),
+			truncate(e.Job.Title, 50), truncate(e.Job.Company, 30), e.Job.Salary, changed)
+	}
+	fmt.Fprintf(tw, "\n%d versions\n", len(entries))
+	return tw.Flush()
+}
+
+// changedFields names the fields that differ between two versions of a job
+func changedFields(prev, next models.Job) []string {
+	var out []string
+	for _, f := range []struct {
+		name       string
+		prev, next string
+	}{
+		{"title", prev.Title, next.Title},
+		{"company", prev.Company, next.Company},
+		{"location", prev.Location, next.Location},
+		{"description", prev.Description, next.Description},
+		{"url", prev.URL, next.URL},
+		{"salary", prev.Salary, next.Salary},
+		{"department", prev.Department, next.Department},
+		{"remote", strconv.FormatBool(prev.Remote), strconv.FormatBool(next.Remote)},
+		{"tags", strings.Join(prev.Tags, ","), strings.Join(next.Tags, ",")},
+	} {
+		if f.prev != f.next {
+			out = append(out, f.name)
+		}
+	}
+	return out
+}
+
+// parseDateFlag accepts an absolute date or a look-back duration ("7d", "36h")
+func parseDateFlag(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse("2006-01-02", v); err == nil {
+		return t, nil
+	}
+	if days, ok := strings.CutSuffix(v, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("bad day count %q", v)
+		}
+		return time.Now().AddDate(0, 0, -n), nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected YYYY-MM-DD or duration, got %q", v)
+	}
+	return time.Now().Add(-d), nil
+}
+
+func writeJobsTable(w io.Writer, jobs []models.Job) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "POSTED\tSOURCE\tCOMPANY\tTITLE\tLOCATION\tREMOTE\tSALARY\tSKILLS")
+	for _, j := range jobs {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%t\t%s\t%s\n",
+			j.PostedAt.Format("2006-01-02"), j.Source, truncate(j.Company, 30), truncate(j.Title, 50),
+			truncate(j.Location, 30), j.Remote, j.Salary, strings.Join(j.Tags, ","))
+	}
+	fmt.Fprintf(tw, "\n%d jobs\n", len(jobs))
+	return tw.Flush()
+}
+
+func writeJobsCSV(w io.Writer, jobs []models.Job) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"id", "title", "company", "location", "remote", "salary", "source", "postedAt", "url", "tags"})
+	for _, j := range jobs {
+		cw.Write([]string{
+			j.ID, j.Title, j.Company, j.Location, strconv.FormatBool(j.Remote), j.Salary,
+			j.Source, j.PostedAt.Format(time.RFC3339), j.URL, strings.Join(j.Tags, ";"),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
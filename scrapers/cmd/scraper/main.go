@@ -1,16 +1,32 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
+	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/groot34/job-aggregator/scraper/internal/browser"
+	"github.com/groot34/job-aggregator/scraper/internal/company"
+	"github.com/groot34/job-aggregator/scraper/internal/discovery"
+	"github.com/groot34/job-aggregator/scraper/internal/feed"
+	"github.com/groot34/job-aggregator/scraper/internal/fixtures"
+	"github.com/groot34/job-aggregator/scraper/internal/health"
+	"github.com/groot34/job-aggregator/scraper/internal/httpcache"
+	"github.com/groot34/job-aggregator/scraper/internal/lifecycle"
 	"github.com/groot34/job-aggregator/scraper/internal/models"
+	"github.com/groot34/job-aggregator/scraper/internal/notify"
 	"github.com/groot34/job-aggregator/scraper/internal/parsers"
 	"github.com/groot34/job-aggregator/scraper/internal/publisher"
-	"github.com/groot34/job-aggregator/scraper/internal/skills"
+	"github.com/groot34/job-aggregator/scraper/internal/scheduler"
+	"github.com/groot34/job-aggregator/scraper/internal/search"
+	"github.com/groot34/job-aggregator/scraper/internal/store"
 	"github.com/joho/godotenv"
 )
 
@@ -18,77 +34,514 @@ func main() {
 	if err := godotenv.Load(); err != nil {
 		log.Println("⚠️  No .env file found, using defaults")
 	}
+	registerSites()
 
-	fmt.Println("🚀 Job Scraper Service Started")
+	// Subcommands; a bare invocation (optionally with run flags) keeps the
+	// original scrape-and-publish behaviour
+	args := os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		switch args[0] {
+		case "run":
+			args = args[1:]
+		case "jobs":
+			os.Exit(runJobsCommand(args[1:]))
+		case "serve":
+			os.Exit(runServeCommand(args[1:]))
+		case "digest":
+			os.Exit(runDigestCommand(args[1:]))
+		case "healthcheck":
+			os.Exit(runHealthcheckCommand(args[1:]))
+		case "sources":
+			os.Exit(runSourcesCommand(args[1:]))
+		case "extract":
+			os.Exit(runExtractCommand(args[1:]))
+		default:
+			fmt.Fprintf(os.Stderr, "unknown command %q\n", args[0])
+			fmt.Fprintln(os.Stderr, "usage: scraper [run [--sources=a,b] [--cache=mode] [--record=dir | --replay=dir] | jobs query|stats|history | serve | digest | healthcheck [--accept=a,b] | sources list | extract <url>...]")
+			os.Exit(2)
+		}
+	}
+
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	cacheMode := fs.String("cache", "", "response cache: off, read, write or readwrite (default $CACHE_MODE or off)")
+	recordDir := fs.String("record", "", "save every fetched page (and rendered DOM) into this fixtures dir")
+	replayDir := fs.String("replay", "", "serve pages from this fixtures dir instead of the network")
+	sources := fs.String("sources", "", "comma-separated sources to scrape, by name or ID prefix (default all)")
+	fs.Parse(args)
+
+	siteParsers, err := parsers.Select(*sources)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	if *recordDir != "" {
+		rec, err := fixtures.NewRecorder(*recordDir)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		fixtures.SetRecorder(rec)
+		fmt.Printf("📼 Recording fixtures into %s\n", *recordDir)
+	}
+	if *replayDir != "" {
+		set, err := fixtures.Load(*replayDir)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		srv := set.Start()
+		defer srv.Close()
+		fixtures.SetReplay(srv.URL)
+		fmt.Printf("📼 Replaying fixtures from %s\n", *replayDir)
+	}
+
+	cache, err := httpcache.FromEnv(*cacheMode)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	httpcache.SetDefault(cache)
 
-	// Registry of parsers
-	siteParsers := []parsers.Parser{
-		&parsers.YCombinatorParser{},
-		&parsers.FreshersworldParser{},
-		&parsers.LinkedInParser{},
-		&parsers.WellfoundParser{},
-		// Add more parsers here
+	fmt.Println("🚀 Job Scraper Service Started")
+	if cache.Mode != httpcache.ModeOff {
+		fmt.Printf("💾 Response cache: %s (%s, ttl %v)\n", cache.Mode, cache.Dir, cache.TTL)
 	}
 
+	defer browser.Shutdown()
 	runScrapers(siteParsers)
 }
 
-func runScrapers(siteParsers []parsers.Parser) {
-	var wg sync.WaitGroup
-	results := make(chan []models.Job, len(siteParsers))
+// registerSites adds the declarative parsers in SITES_DIR and the job feeds
+// in JOB_FEEDS_PATH to the registry
+func registerSites() {
+	dir := os.Getenv("SITES_DIR")
+	if dir == "" {
+		dir = "sites"
+	}
+	if err := parsers.RegisterSites(dir); err != nil {
+		log.Printf("⚠️  Skipping site definitions: %v\n", err)
+	}
 
-	for _, p := range siteParsers {
-		wg.Add(1)
-		go func(parser parsers.Parser) {
-			defer wg.Done()
-			fmt.Printf("🕷️  Starting scraper for: %s\n", parser.Name())
+	feeds := os.Getenv("JOB_FEEDS_PATH")
+	if feeds == "" {
+		feeds = "jobfeeds.yaml"
+	}
+	if err := parsers.RegisterFeeds(feeds); err != nil {
+		log.Printf("⚠️  Skipping job feeds: %v\n", err)
+	}
+}
 
-			// For YC and others we don't need a specific URL in Parse() args
-			// But for interface consistency we might pass a dummy or specific start URL
-			jobs, err := parser.Parse("")
-			if err != nil {
-				log.Printf("❌ Error scraping %s: %v\n", parser.Name(), err)
-				return
-			}
-			results <- jobs
-		}(p)
+// scrapeResult summarizes a finished parser's output; the jobs themselves went to emit
+type scrapeResult struct {
+	parser  parsers.Parser
+	source  string
+	metrics health.Metrics
+	err     error
+}
+
+// scrapeAll runs the parsers on a worker pool configured by SCHEDULER_*, passing
+// each job to emit as soon as it is found; emit may be called from several
+// tasks at once. The channel gets one result per parser and closes once all
+// have finished.
+//
+// A parser that scrapes several boards or pages runs one task per board, and
+// one per job for detail pages, so SCHEDULER_PER_SOURCE and SCHEDULER_LIMITS
+// cap how many of a source's requests are in flight and no source with many
+// boards holds the pool while the others wait.
+func scrapeAll(siteParsers []parsers.Parser, emit func(models.Job)) <-chan scrapeResult {
+	results := make(chan scrapeResult, len(siteParsers))
+	sched := scheduler.New(scheduler.ConfigFromEnv())
+
+	for _, parser := range siteParsers {
+		// Browser scrapes take longest, so start them first rather than last
+		priority := 0
+		if info, ok := parsers.Lookup(parser.Name()); ok && info.Headless {
+			priority = 1
+		}
+
+		parts := []string{""}
+		if s, ok := parser.(parsers.Splitter); ok && len(s.Parts()) > 0 {
+			parts = s.Parts()
+		}
+		var fill func(*models.Job) bool
+		if d, ok := parser.(parsers.Detailer); ok {
+			fill = d.Detail()
+		}
+
+		run := &parserRun{parser: parser, tasks: len(parts), results: results}
+		if len(parts) > 1 {
+			fmt.Printf("🕷️  Starting scraper for: %s (%d boards)\n", parser.Name(), len(parts))
+		} else {
+			fmt.Printf("🕷️  Starting scraper for: %s\n", parser.Name())
+		}
+		for _, part := range parts {
+			sched.Submit(scheduler.Task{Source: parser.Name(), Priority: priority, Run: func() {
+				err := parsers.Stream(parser, part, func(j models.Job) error {
+					if fill == nil {
+						run.add(j)
+						emit(j)
+						return nil
+					}
+					// The detail page is a task of its own, queued behind the source's other work
+					run.start()
+					sched.Submit(scheduler.Task{Source: parser.Name(), Priority: priority, Run: func() {
+						if fill(&j) {
+							run.add(j)
+							emit(j)
+						}
+						run.finish("", nil)
+					}})
+					return nil
+				})
+				run.finish(part, err)
+			}})
+		}
 	}
 
 	// Close channel when all done
 	go func() {
-		wg.Wait()
+		sched.Wait()
+		sched.Close()
+		fmt.Printf("⚙️  Scheduler: %s\n", sched.Summary())
 		close(results)
 	}()
+	return results
+}
+
+// parserRun gathers one parser's tasks into its scrapeResult, which is sent
+// once the last of them finishes
+type parserRun struct {
+	parser  parsers.Parser
+	results chan<- scrapeResult
+
+	mu     sync.Mutex
+	meter  health.Meter
+	tasks  int
+	failed []string
+	err    error
+}
+
+// add measures a job the parser emitted
+func (r *parserRun) add(j models.Job) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.meter.Add(j)
+}
+
+// start counts a task queued by one of the parser's running tasks
+func (r *parserRun) start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tasks++
+}
+
+// finish records how a task went. A failed board or page fails just that
+// part; a parser that isn't split fails as a whole.
+func (r *parserRun) finish(part string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var partial *parsers.BoardsError
+	switch {
+	case errors.As(err, &partial):
+		r.failed = append(r.failed, partial.Failed...)
+	case err != nil && part != "":
+		fmt.Printf("❌ %s %s: %v\n", r.parser.Name(), part, err)
+		r.failed = append(r.failed, part)
+	case err != nil:
+		r.err = err
+	}
+
+	if r.tasks--; r.tasks > 0 {
+		return
+	}
+	res := scrapeResult{parser: r.parser, source: r.parser.Name(), metrics: r.meter.Metrics(), err: r.err}
+	if res.err == nil && len(r.failed) > 0 {
+		res.err = &parsers.BoardsError{Source: res.source, Failed: r.failed}
+	}
+	r.results <- res
+}
+
+func runScrapers(siteParsers []parsers.Parser) {
+	monitor := newHealthMonitor()
+	companies := loadCompanies()
+	now := time.Now()
+
+	var observed, discoverable []models.Job
+	p, err := buildPipeline(companies, now, &observed, &discoverable)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	// Parsers feed the pipeline job by job, so early sources are filtered and
+	// published while later ones are still scraping; when a stage falls behind,
+	// sends block and the parsers wait. Only the jobs that make it through every
+	// stage are collected, for the steps that need the whole run.
+	in := make(chan models.Job)
+	fed := make(chan []lifecycle.Scrape, 1)
+	go func() {
+		defer close(in)
+		var scraped []lifecycle.Scrape
+		for res := range scrapeAll(siteParsers, func(j models.Job) { in <- j }) {
+			monitor.observe(res)
+			var partial *parsers.BoardsError
+			switch {
+			case errors.As(res.err, &partial):
+				// The boards that loaded are complete; only the failed ones keep their jobs open
+				log.Printf("⚠️  Error scraping %v\n", res.err)
+			case res.err != nil:
+				// Jobs emitted before the failure are kept, but the source isn't
+				// complete enough to close the jobs it no longer lists
+				log.Printf("❌ Error scraping %s: %v\n", res.source, res.err)
+				continue
+			}
+			// Some parsers swallow errors and return nothing; don't let that close every job
+			if res.metrics.Jobs > 0 {
+				s := lifecycle.Scrape{Source: res.source}
+				if partial != nil {
+					s.Failed = partial.Failed
+				}
+				scraped = append(scraped, s)
+			}
+		}
+		fed <- scraped
+	}()
+
+	allFilteredJobs, err := p.Run(context.Background(), in)
+	scraped := <-fed
+	fmt.Printf("🔗 Pipeline: %s\n", p.Summary())
+	if err != nil {
+		// A stage that stopped early saw only part of the run; missing jobs aren't closed ones
+		log.Printf("❌ Pipeline failed, skipping lifecycle tracking: %v\n", err)
+	} else {
+		notifyNewJobs(trackLifecycle(observed, allFilteredJobs, scraped))
+	}
+	storeJobs(allFilteredJobs)
+	writeFeeds(allFilteredJobs)
+	discoverCompanies(discoverable)
+	saveCompanies(companies)
+	monitor.finish()
+	if len(monitor.anomalies) > 0 {
+		fmt.Printf("🩺 %d parser health anomalies detected (run `scraper healthcheck` for details)\n", len(monitor.anomalies))
+	}
 
-	// Collect results
-	var allFilteredJobs []models.Job
-	for jobs := range results {
-		for _, j := range jobs {
-			// Skill & Domain Filtering
-			// Check if it's a software job
-			fullText := j.Title + " " + j.Description + " " + strings.Join(j.Tags, " ")
-			if !skills.IsSoftwareJob(fullText) {
+	fmt.Printf("\n🏁 Scrape finished. Total valid jobs processed: %d\n", len(allFilteredJobs))
+}
+
+// trackLifecycle records first/last-seen times for every job the sources listed
+// and forwards the status changes. Changes to jobs that didn't make it through
+// the pipeline are recorded but not forwarded; closures always are.
+func trackLifecycle(listed, kept []models.Job, scraped []lifecycle.Scrape) []lifecycle.Event {
+	tracker, err := lifecycle.Load(lifecycle.Path())
+	if err != nil {
+		log.Printf("❌ Failed to load lifecycle state: %v\n", err)
+		return nil
+	}
+
+	all := tracker.Observe(listed, scraped, time.Now())
+	if err := tracker.Save(); err != nil {
+		log.Printf("❌ Failed to save lifecycle state: %v\n", err)
+	}
+
+	// Forwarded events carry the job as the pipeline left it, tags and all
+	published := make(map[string]*models.Job, len(kept))
+	for i := range kept {
+		published[kept[i].ID] = &kept[i]
+	}
+	var events []lifecycle.Event
+	for _, e := range all {
+		if e.Status != lifecycle.StatusClosed {
+			j, ok := published[e.JobID]
+			if !ok {
 				continue
 			}
+			e.Job = j
+		}
+		events = append(events, e)
+	}
+
+	counts := make(map[lifecycle.Status]int)
+	for _, e := range events {
+		counts[e.Status]++
+	}
+	fmt.Printf("🔄 Lifecycle: %d new, %d updated, %d closed, %d reopened\n",
+		counts[lifecycle.StatusNew], counts[lifecycle.StatusUpdated],
+		counts[lifecycle.StatusClosed], counts[lifecycle.StatusReopened])
 
-			// Add extracted skills to the job model
-			j.Tags = skills.ExtractSkills(fullText)
-			allFilteredJobs = append(allFilteredJobs, j)
+	if err := publisher.PublishEvents(events); err != nil {
+		log.Printf("❌ Failed to publish lifecycle events: %v\n", err)
+	}
+	return events
+}
 
-			// Print for demo
-			// fmt.Printf("   Found: %s\n", j.Title)
+// loadCompanies opens the company directory that normalizes company names and
+// gives jobs a CompanyID; nil (and jobs keep their raw names) if it can't be read
+func loadCompanies() *company.Directory {
+	dir, err := company.Load(company.Path())
+	if err != nil {
+		log.Printf("❌ %v\n", err)
+		return nil
+	}
+	return dir
+}
+
+// saveCompanies enriches the directory with the websites discovery found, then persists it
+func saveCompanies(dir *company.Directory) {
+	if dir == nil {
+		return
+	}
+	if found, err := discovery.Load(discovery.Path()); err == nil {
+		for _, c := range found.Companies() {
+			if c.Website != "" {
+				dir.SetDomain(c.Name, c.Website)
+			}
 		}
 	}
+	if err := dir.Save(); err != nil {
+		log.Printf("❌ Failed to save companies: %v\n", err)
+		return
+	}
+	fmt.Printf("🏢 Company directory: %d companies\n", len(dir.Companies()))
+}
 
-	if len(allFilteredJobs) > 0 {
-		fmt.Printf("📦 Preparing to send %d valid jobs to backend...\n", len(allFilteredJobs))
-		if err := publisher.PublishJobs(allFilteredJobs); err != nil {
-			log.Printf("❌ Failed to publish jobs: %v\n", err)
+// discoverCompanies follows the company pages behind this run's jobs to their
+// own ATS boards and careers pages, which the ATS and JobPosting sources scrape
+// on later runs. Every job counts here, not just the software ones.
+func discoverCompanies(jobs []models.Job) {
+	max := 25
+	if v := os.Getenv("DISCOVERY_MAX_COMPANIES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			log.Printf("⚠️  Invalid DISCOVERY_MAX_COMPANIES %q, using %d\n", v, max)
+		} else {
+			max = n
+		}
+	}
+	if max <= 0 {
+		return
+	}
+	ttl := 30 * 24 * time.Hour
+	if v := os.Getenv("DISCOVERY_TTL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			log.Printf("⚠️  Invalid DISCOVERY_TTL %q, using %v\n", v, ttl)
+		} else {
+			ttl = d
 		}
 	}
 
-	fmt.Printf("\n🏁 Scrape finished. Total valid jobs processed: %d\n", len(allFilteredJobs))
+	store, err := discovery.Load(discovery.Path())
+	if err != nil {
+		log.Printf("❌ %v\n", err)
+		return
+	}
+	report := discovery.New(store, ttl, max).Run(jobs, time.Now())
+	if report.Checked+report.Failed == 0 {
+		return
+	}
+	if err := store.Save(); err != nil {
+		log.Printf("❌ Failed to save discovery state: %v\n", err)
+	}
+	fmt.Printf("🧭 Discovery: checked %d companies, found %d ATS boards and %d careers pages (%d failed)\n",
+		report.Checked, report.Boards, report.CareersPages, report.Failed)
+}
+
+// notifyNewJobs fires webhooks for newly seen jobs that match a saved search
+func notifyNewJobs(events []lifecycle.Event) {
+	rulesPath := os.Getenv("WEBHOOKS_PATH")
+	if rulesPath == "" {
+		rulesPath = "webhooks.json"
+	}
+	if _, err := os.Stat(rulesPath); os.IsNotExist(err) {
+		return
+	}
+
+	var newJobs []models.Job
+	for _, e := range events {
+		if e.Status == lifecycle.StatusNew && e.Job != nil {
+			newJobs = append(newJobs, *e.Job)
+		}
+	}
+
+	rules, err := notify.LoadRules(rulesPath)
+	if err != nil {
+		log.Printf("❌ %v\n", err)
+		return
+	}
+	searches, err := loadSearches()
+	if err != nil {
+		log.Printf("❌ %v\n", err)
+		return
+	}
+
+	statePath := os.Getenv("NOTIFY_STATE_PATH")
+	if statePath == "" {
+		statePath = "data/notify.json"
+	}
+	notifier, err := notify.New(rules, searches, statePath)
+	if err != nil {
+		log.Printf("❌ Failed to set up webhooks: %v\n", err)
+		return
+	}
+
+	report := notifier.Notify(newJobs, time.Now())
+	if err := notifier.Save(); err != nil {
+		log.Printf("❌ Failed to save notify state: %v\n", err)
+	}
+	fmt.Printf("🔔 Webhooks: %d sent (%d retried), %d duplicates, %d rate-limited, %d failed, %d dropped\n",
+		report.Sent, report.Retried, report.Duplicates, report.RateLimited, report.Failed, report.Dropped)
+}
+
+// storeJobs keeps a local copy of every processed job for offline querying
+func storeJobs(jobs []models.Job) {
+	db, err := store.Open(storePath())
+	if err != nil {
+		log.Printf("❌ Failed to open job store: %v\n", err)
+		return
+	}
+	defer db.Close()
 
-	// Keep alive if needed (e.g. cron mode), for now exit
-	time.Sleep(2 * time.Second)
+	if err := db.SaveJobs(jobs); err != nil {
+		log.Printf("❌ Failed to store jobs: %v\n", err)
+		return
+	}
+	fmt.Printf("💾 Stored %d jobs locally\n", len(jobs))
+}
+
+func storePath() string {
+	if p := os.Getenv("STORE_PATH"); p != "" {
+		return p
+	}
+	return "data/jobs.db"
+}
+
+// writeFeeds renders each saved search as RSS, Atom and JSON Feed files when FEEDS_DIR is set
+func writeFeeds(jobs []models.Job) {
+	dir := os.Getenv("FEEDS_DIR")
+	if dir == "" {
+		return
+	}
+
+	searches, err := loadSearches()
+	if err != nil {
+		log.Printf("❌ %v\n", err)
+		return
+	}
+	for _, s := range searches {
+		if err := feed.WriteFiles(dir, s, os.Getenv("FEEDS_BASE_URL"), jobs); err != nil {
+			log.Printf("❌ Failed to write feed %q: %v\n", s.Name, err)
+		}
+	}
+	if len(searches) > 0 {
+		fmt.Printf("📰 Wrote feeds for %d saved searches to %s\n", len(searches), dir)
+	}
+}
+
+// loadSearches reads SEARCHES_PATH; a missing file simply means no saved searches
+func loadSearches() ([]search.Saved, error) {
+	path := os.Getenv("SEARCHES_PATH")
+	if path == "" {
+		path = "searches.json"
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+	return search.Load(path)
 }
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/company"
+	"github.com/groot34/job-aggregator/scraper/internal/filter"
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+	"github.com/groot34/job-aggregator/scraper/internal/pipeline"
+	"github.com/groot34/job-aggregator/scraper/internal/publisher"
+)
+
+// defaultStages is the processing order when PIPELINE_STAGES is unset. "discover"
+// only notes the jobs whose company pages are followed once the run is over.
+const defaultStages = "normalize,filter,discover,classify,enrich,dedupe,publish"
+
+// buildPipeline assembles the stages named in PIPELINE_STAGES. Jobs reaching
+// the discover stage are appended to *discoverable. Every job the sources
+// listed is appended to *observed just before filtering, so lifecycle tracking
+// sees postings that filters drop as still listed rather than closed.
+func buildPipeline(companies *company.Directory, now time.Time, observed, discoverable *[]models.Job) (*pipeline.Pipeline, error) {
+	available := map[string]pipeline.Stage{
+		"normalize": pipeline.Map("normalize", func(j models.Job) (models.Job, bool) { return j, true }),
+		"filter":    filterStage(companies, now),
+		"discover":  pipeline.Tap("discover", func(j models.Job) { *discoverable = append(*discoverable, j) }),
+		"classify":  pipeline.Classify(),
+		"enrich":    pipeline.Enrich(),
+		"dedupe":    pipeline.Dedupe(),
+		"publish": pipeline.Publish(envInt("PUBLISH_BATCH_SIZE", 100), func(jobs []models.Job) error {
+			fmt.Printf("📦 Preparing to send %d valid jobs to backend...\n", len(jobs))
+			return publisher.PublishJobs(jobs)
+		}, func(err error) {
+			log.Printf("❌ Failed to publish jobs: %v\n", err)
+		}),
+	}
+	if companies != nil {
+		available["normalize"] = pipeline.Normalize(companies, now)
+	}
+
+	list := os.Getenv("PIPELINE_STAGES")
+	if list == "" {
+		list = defaultStages
+	}
+	stages, err := pipeline.Select(available, list)
+	if err != nil {
+		return nil, err
+	}
+
+	// Not a selectable stage: leaving it out would close every job
+	observe := pipeline.Tap("observe", func(j models.Job) { *observed = append(*observed, j) })
+	at := len(stages)
+	for i, s := range stages {
+		if s.Name() == "filter" {
+			at = i
+			break
+		}
+	}
+	stages = append(stages[:at], append([]pipeline.Stage{observe}, stages[at:]...)...)
+
+	return pipeline.New(envInt("PIPELINE_BUFFER", 64), stages...), nil
+}
+
+// envInt reads a non-negative integer setting, falling back to def when unset or invalid
+func envInt(name string, def int) int {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		log.Printf("⚠️  Invalid %s %q, using %d\n", name, v, def)
+		return def
+	}
+	return n
+}
+
+// filterStage drops blocked companies, staffing agencies, withheld employers and
+// reposts per FILTERS_PATH, and writes what it dropped to FILTER_REPORT_PATH.
+// The previous report tells it which posting of each repost group to keep.
+// A broken config leaves jobs unfiltered rather than failing the run.
+func filterStage(companies *company.Directory, now time.Time) pipeline.Stage {
+	passthrough := pipeline.Map("filter", func(j models.Job) (models.Job, bool) { return j, true })
+
+	cfg, err := filter.Load(filter.Path())
+	if err != nil {
+		log.Printf("❌ %v (jobs left unfiltered)\n", err)
+		return passthrough
+	}
+	f, err := filter.New(cfg)
+	if err != nil {
+		log.Printf("❌ %v (jobs left unfiltered)\n", err)
+		return passthrough
+	}
+	if companies != nil {
+		f.CompanyDomain = func(j models.Job) string {
+			c, _ := companies.Get(j.CompanyID)
+			return c.Domain
+		}
+	}
+
+	reportPath := os.Getenv("FILTER_REPORT_PATH")
+	if reportPath == "" {
+		reportPath = "data/filter-report.json"
+	}
+	last, err := filter.LoadReport(reportPath)
+	if err != nil {
+		log.Printf("⚠️  %v; repost groups may pick new survivors\n", err)
+	}
+
+	return pipeline.Filter(f, now, last.Reposts, func(report filter.Report) {
+		counts := report.Counts()
+		fmt.Printf("🧹 Filtered %d of %d jobs: %d blocked, %d staffing, %d confidential, %d reposts, %d templates (%d allowlisted)\n",
+			len(report.Dropped), report.Checked, counts[filter.ReasonBlocked], counts[filter.ReasonStaffing],
+			counts[filter.ReasonConfidential], counts[filter.ReasonRepost], counts[filter.ReasonTemplate], report.Allowed)
+
+		if err := report.Save(reportPath); err != nil {
+			log.Printf("❌ %v\n", err)
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/api"
+	"github.com/groot34/job-aggregator/scraper/internal/store"
+)
+
+// runServeCommand handles `scraper serve` and returns the exit code
+func runServeCommand(args []string) int {
+	defaultAddr := os.Getenv("SERVE_ADDR")
+	if defaultAddr == "" {
+		defaultAddr = ":8080"
+	}
+
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", defaultAddr, "listen address")
+	dbPath := fs.String("db", storePath(), "path to the job store")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	db, err := store.OpenReadOnly(*dbPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer db.Close()
+
+	searches, err := loadSearches()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	handler := api.NewServer(db)
+	handler.ServeFeeds(searches, os.Getenv("FEEDS_BASE_URL"))
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf("🌐 Serving jobs API on %s\n", *addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Println("👋 Server stopped")
+	return 0
+}
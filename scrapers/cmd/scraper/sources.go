@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/groot34/job-aggregator/scraper/internal/parsers"
+)
+
+// runSourcesCommand handles `scraper sources list`
+func runSourcesCommand(args []string) int {
+	if len(args) == 0 || args[0] != "list" {
+		fmt.Fprintln(os.Stderr, "usage: scraper sources list")
+		return 2
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tPREFIX\tHEADLESS\tPAGINATION\tDETAIL PAGES\tRATE LIMIT")
+	for _, info := range parsers.Registered() {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			info.Name, info.IDPrefix, yesNo(info.Headless), yesNo(info.Pagination), yesNo(info.DetailPages || parsers.DetailPagesEnabled(info.Name)), rateLimit(info))
+	}
+	if err := tw.Flush(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// rateLimit summarises a parser's default politeness, e.g. "5s +3s, 1 parallel"
+func rateLimit(info parsers.Info) string {
+	l := info.RateLimit
+	if l == nil {
+		return "default"
+	}
+	s := l.Delay.String()
+	if l.RandomDelay > 0 {
+		s += " +" + l.RandomDelay.String()
+	}
+	if l.Parallelism > 0 {
+		s += fmt.Sprintf(", %d parallel", l.Parallelism)
+	}
+	return s
+}
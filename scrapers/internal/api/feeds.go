@@ -0,0 +1,91 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/feed"
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+	"github.com/groot34/job-aggregator/scraper/internal/search"
+	"github.com/groot34/job-aggregator/scraper/internal/store"
+)
+
+// feedWindow caps how many matching jobs a feed lists, newest first
+const feedWindow = 500
+
+// ServeFeeds exposes RSS, Atom and JSON Feed documents for each saved search
+// at /feeds/{name}/{format}, with /feeds listing what is available.
+func (s *Server) ServeFeeds(searches []search.Saved, baseURL string) {
+	s.mux.HandleFunc("GET /feeds", func(w http.ResponseWriter, r *http.Request) {
+		type feedLinks struct {
+			Name  string            `json:"name"`
+			Links map[string]string `json:"links"`
+		}
+		out := []feedLinks{}
+		for _, sv := range searches {
+			links := make(map[string]string)
+			for _, f := range feed.Formats {
+				links[string(f)] = "/feeds/" + sv.Slug() + "/" + string(f)
+			}
+			out = append(out, feedLinks{Name: sv.Name, Links: links})
+		}
+		writeJSON(w, http.StatusOK, out)
+	})
+
+	s.mux.HandleFunc("GET /feeds/{name}/{format}", func(w http.ResponseWriter, r *http.Request) {
+		sv, ok := search.Find(searches, r.PathValue("name"))
+		if !ok {
+			writeError(w, http.StatusNotFound, "feed not found")
+			return
+		}
+		format := feed.Format(r.PathValue("format"))
+		switch format {
+		case feed.FormatRSS, feed.FormatAtom, feed.FormatJSON:
+		default:
+			writeError(w, http.StatusNotFound, "unknown feed format")
+			return
+		}
+
+		jobs, err := s.matchingJobs(sv)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "error fetching jobs")
+			log.Printf("❌ Feed query failed: %v", err)
+			return
+		}
+
+		w.Header().Set("Content-Type", format.ContentType())
+		if err := feed.Write(w, format, feed.MetaFor(sv, baseURL), jobs); err != nil {
+			log.Printf("❌ Failed to write feed: %v", err)
+		}
+	})
+}
+
+// matchingJobs returns up to feedWindow jobs matching sv. Everything but
+// seniority, which is inferred from titles, is filtered in the query, and the
+// store is paged until the window is full so narrow searches still reach older jobs.
+func (s *Server) matchingJobs(sv search.Saved) ([]models.Job, error) {
+	f := store.Filter{
+		Skills:   sv.Skills,
+		Location: sv.Location,
+		Remote:   sv.Remote,
+		Since:    sv.Since(time.Now()),
+		Limit:    feedWindow,
+	}
+
+	var matched []models.Job
+	for {
+		page, err := s.store.Query(f)
+		if err != nil {
+			return nil, err
+		}
+		matched = append(matched, sv.Filter(page)...)
+		if len(matched) >= feedWindow {
+			return matched[:feedWindow], nil
+		}
+		if len(page) < f.Limit {
+			return matched, nil
+		}
+		f.Offset += f.Limit
+	}
+}
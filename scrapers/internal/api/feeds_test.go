@@ -0,0 +1,68 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+	"github.com/groot34/job-aggregator/scraper/internal/search"
+	"github.com/groot34/job-aggregator/scraper/internal/store"
+)
+
+func TestFeedReachesOlderMatches(t *testing.T) {
+	db, err := store.Open(filepath.Join(t.TempDir(), "jobs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// More recent non-matching jobs than the feed window, then a few old matches
+	now := time.Now()
+	var jobs []models.Job
+	for i := 0; i < feedWindow+50; i++ {
+		jobs = append(jobs, models.Job{
+			ID: fmt.Sprintf("new-%d", i), Title: "Frontend Engineer", Company: "Acme", Source: "Test",
+			Tags: []string{"React"}, PostedAt: now.Add(-time.Duration(i) * time.Minute),
+		})
+	}
+	for i := 0; i < 3; i++ {
+		jobs = append(jobs, models.Job{
+			ID: fmt.Sprintf("old-%d", i), Title: "Backend Engineer", Company: "Globex", Source: "Test",
+			Tags: []string{"Go", "Kubernetes"}, Remote: true, PostedAt: now.AddDate(0, 0, -3-i),
+		})
+	}
+	// Too old for the search's 30-day window
+	jobs = append(jobs, models.Job{
+		ID: "ancient", Title: "Backend Engineer", Company: "Globex", Source: "Test",
+		Tags: []string{"go", "kubernetes"}, Remote: true, PostedAt: now.AddDate(0, 0, -60),
+	})
+	if err := db.SaveJobs(jobs); err != nil {
+		t.Fatal(err)
+	}
+
+	remote := true
+	srv := NewServer(db)
+	srv.ServeFeeds([]search.Saved{{Name: "Go remote", Skills: []string{"go", "kubernetes"}, Remote: &remote, MaxAge: 30}}, "")
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/feeds/go-remote/json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var doc struct {
+		Items []struct {
+			ID string `json:"id"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Items) != 3 {
+		t.Errorf("feed has %d items, want the 3 older matches: %s", len(doc.Items), rec.Body)
+	}
+}
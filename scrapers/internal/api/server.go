@@ -0,0 +1,212 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+	"github.com/groot34/job-aggregator/scraper/internal/store"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// Server exposes the local job store as a read-only REST API
+type Server struct {
+	store *store.Store
+	mux   *http.ServeMux
+}
+
+// NewServer wires the routes for db
+func NewServer(db *store.Store) *Server {
+	s := &Server{store: db, mux: http.NewServeMux()}
+
+	s.mux.HandleFunc("GET /jobs", s.handleJobs)
+	s.mux.HandleFunc("GET /jobs/{id}", s.handleJob)
+	s.mux.HandleFunc("GET /sources", s.handleSources)
+	s.mux.HandleFunc("GET /skills", s.handleSkills)
+	s.mux.HandleFunc("GET /healthz", s.handleHealth)
+	s.mux.HandleFunc("GET /readyz", s.handleReady)
+
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	s.mux.ServeHTTP(w, r)
+	log.Printf("🌐 %s %s (%v)", r.Method, r.URL.RequestURI(), time.Since(start).Round(time.Millisecond))
+}
+
+// jobsResponse mirrors the shape of the Node backend's GET /api/jobs
+type jobsResponse struct {
+	Jobs        []models.Job `json:"jobs"`
+	Total       int          `json:"total"`
+	CurrentPage int          `json:"currentPage"`
+	TotalPages  int          `json:"totalPages"`
+}
+
+func (s *Server) handleJobs(w http.ResponseWriter, r *http.Request) {
+	filter, page, err := parseJobsQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	jobs, err := s.store.Query(filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "error fetching jobs")
+		log.Printf("❌ Query failed: %v", err)
+		return
+	}
+	total, err := s.store.Count(filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "error fetching jobs")
+		log.Printf("❌ Count failed: %v", err)
+		return
+	}
+
+	if jobs == nil {
+		jobs = []models.Job{}
+	}
+	writeJSON(w, http.StatusOK, jobsResponse{
+		Jobs:        jobs,
+		Total:       total,
+		CurrentPage: page,
+		TotalPages:  (total + filter.Limit - 1) / filter.Limit,
+	})
+}
+
+func (s *Server) handleJob(w http.ResponseWriter, r *http.Request) {
+	job, found, err := s.store.Get(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "error fetching job")
+		log.Printf("❌ Get failed: %v", err)
+		return
+	}
+	if !found {
+		writeError(w, http.StatusNotFound, "job not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+func (s *Server) handleSources(w http.ResponseWriter, r *http.Request) {
+	sources, err := s.store.Sources()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "error fetching sources")
+		log.Printf("❌ Sources failed: %v", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, sources)
+}
+
+func (s *Server) handleSkills(w http.ResponseWriter, r *http.Request) {
+	skills, err := s.store.Skills()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "error fetching skills")
+		log.Printf("❌ Skills failed: %v", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, skills)
+}
+
+// handleHealth reports liveness: the process is up and serving
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleReady reports readiness: the store can actually be queried
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	if err := s.store.Ping(); err != nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "unavailable", "error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+}
+
+// parseJobsQuery turns /jobs query parameters into a store filter and the requested page
+func parseJobsQuery(r *http.Request) (store.Filter, int, error) {
+	q := r.URL.Query()
+	f := store.Filter{
+		Skill:     q.Get("skill"),
+		Source:    q.Get("source"),
+		CompanyID: q.Get("companyId"),
+		Location:  q.Get("location"),
+		Sort:      q.Get("sort"),
+		Limit:     defaultLimit,
+	}
+
+	if v := q.Get("remote"); v != "" {
+		remote, err := strconv.ParseBool(v)
+		if err != nil {
+			return f, 0, fmt.Errorf("invalid remote %q", v)
+		}
+		f.Remote = &remote
+	}
+	if v := q.Get("minSalary"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return f, 0, fmt.Errorf("invalid minSalary %q", v)
+		}
+		f.MinSalary = n
+	}
+	for name, dst := range map[string]*time.Time{"since": &f.Since, "until": &f.Until} {
+		if v := q.Get(name); v != "" {
+			t, err := parseTime(v)
+			if err != nil {
+				return f, 0, fmt.Errorf("invalid %s %q", name, v)
+			}
+			*dst = t
+		}
+	}
+	if f.Sort != "" {
+		if _, ok := store.SortFields[strings.TrimPrefix(f.Sort, "-")]; !ok {
+			return f, 0, fmt.Errorf("invalid sort %q", f.Sort)
+		}
+	}
+
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return f, 0, fmt.Errorf("invalid limit %q", v)
+		}
+		f.Limit = min(n, maxLimit)
+	}
+	page := 1
+	if v := q.Get("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return f, 0, fmt.Errorf("invalid page %q", v)
+		}
+		page = n
+	}
+	f.Offset = (page - 1) * f.Limit
+
+	return f, page, nil
+}
+
+func parseTime(v string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", v); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("❌ Failed to write response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"message": message})
+}
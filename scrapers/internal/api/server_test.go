@@ -0,0 +1,160 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+	"github.com/groot34/job-aggregator/scraper/internal/store"
+)
+
+// testServer serves a temp store holding a handful of jobs, opened read-only as `serve` does
+func testServer(t *testing.T) *Server {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "jobs.db")
+	db, err := store.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := func(n int) time.Time { return time.Date(2024, 3, n, 12, 0, 0, 0, time.UTC) }
+	err = db.SaveJobs([]models.Job{
+		{ID: "a", Title: "Go Engineer", Company: "Acme", Location: "Berlin", Source: "Lever", PostedAt: day(1), Salary: "€70K - €90K", Tags: []string{"Go", "Postgres"}},
+		{ID: "b", Title: "Backend Developer", Company: "Globex", Location: "Remote", Source: "Greenhouse", PostedAt: day(3), Remote: true, Salary: "$120K - $150K", Tags: []string{"Go"}},
+		{ID: "c", Title: "Data Engineer", Company: "Initech", Location: "Pune", Source: "Lever", PostedAt: day(2), Tags: []string{"Python"}},
+	})
+	db.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ro, err := store.OpenReadOnly(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ro.Close() })
+	return NewServer(ro)
+}
+
+// get serves one request and decodes the JSON response into v
+func get(t *testing.T, srv *Server, target string, v interface{}) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("%s: Content-Type = %q", target, ct)
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("%s: %v: %s", target, err, rec.Body)
+	}
+	return rec.Code
+}
+
+func TestJobs(t *testing.T) {
+	srv := testServer(t)
+	for _, tc := range []struct {
+		query              string
+		want               []string
+		total, page, pages int
+	}{
+		{"", []string{"b", "c", "a"}, 3, 1, 1},
+		{"?skill=go", []string{"b", "a"}, 2, 1, 1},
+		{"?source=lever&sort=title", []string{"c", "a"}, 2, 1, 1},
+		{"?remote=true", []string{"b"}, 1, 1, 1},
+		{"?minSalary=100000", []string{"b"}, 1, 1, 1},
+		{"?location=pune", []string{"c"}, 1, 1, 1},
+		{"?since=2024-03-02&until=2024-03-03", []string{"c"}, 1, 1, 1},
+		{"?sort=-salary", []string{"b", "a", "c"}, 3, 1, 1},
+		{"?limit=2", []string{"b", "c"}, 3, 1, 2},
+		{"?limit=2&page=2", []string{"a"}, 3, 2, 2},
+		{"?limit=2&page=3", []string{}, 3, 3, 2},
+	} {
+		var resp struct {
+			Jobs        []models.Job `json:"jobs"`
+			Total       int          `json:"total"`
+			CurrentPage int          `json:"currentPage"`
+			TotalPages  int          `json:"totalPages"`
+		}
+		if code := get(t, srv, "/jobs"+tc.query, &resp); code != http.StatusOK {
+			t.Errorf("%s: status %d", tc.query, code)
+			continue
+		}
+		got := []string{}
+		for _, j := range resp.Jobs {
+			got = append(got, j.ID)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("/jobs%s = %v, want %v", tc.query, got, tc.want)
+		}
+		if resp.Total != tc.total || resp.CurrentPage != tc.page || resp.TotalPages != tc.pages {
+			t.Errorf("/jobs%s: total %d, page %d of %d; want %d, %d of %d",
+				tc.query, resp.Total, resp.CurrentPage, resp.TotalPages, tc.total, tc.page, tc.pages)
+		}
+	}
+}
+
+func TestJobsRejectsBadQueries(t *testing.T) {
+	srv := testServer(t)
+	for _, query := range []string{"?remote=maybe", "?minSalary=lots", "?since=yesterday", "?sort=bogus", "?limit=0", "?page=-1"} {
+		var resp map[string]string
+		if code := get(t, srv, "/jobs"+query, &resp); code != http.StatusBadRequest || resp["message"] == "" {
+			t.Errorf("/jobs%s = %d %v, want 400 with a message", query, code, resp)
+		}
+	}
+}
+
+func TestJob(t *testing.T) {
+	srv := testServer(t)
+
+	var job models.Job
+	if code := get(t, srv, "/jobs/b", &job); code != http.StatusOK || job.Title != "Backend Developer" || !job.Remote {
+		t.Errorf("/jobs/b = %d %+v", code, job)
+	}
+	var resp map[string]string
+	if code := get(t, srv, "/jobs/missing", &resp); code != http.StatusNotFound || resp["message"] != "job not found" {
+		t.Errorf("/jobs/missing = %d %v", code, resp)
+	}
+}
+
+func TestCounts(t *testing.T) {
+	srv := testServer(t)
+	for target, want := range map[string][]store.Count{
+		"/sources": {{Name: "Lever", Count: 2}, {Name: "Greenhouse", Count: 1}},
+		"/skills":  {{Name: "Go", Count: 2}, {Name: "Postgres", Count: 1}, {Name: "Python", Count: 1}},
+	} {
+		var got []store.Count
+		if code := get(t, srv, target, &got); code != http.StatusOK || !reflect.DeepEqual(got, want) {
+			t.Errorf("%s = %d %v, want %v", target, code, got, want)
+		}
+	}
+}
+
+func TestHealth(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.db")
+	db, err := store.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := NewServer(db)
+
+	for target, want := range map[string]string{"/healthz": "ok", "/readyz": "ready"} {
+		var resp map[string]string
+		if code := get(t, srv, target, &resp); code != http.StatusOK || resp["status"] != want {
+			t.Errorf("%s = %d %v, want %s", target, code, resp, want)
+		}
+	}
+
+	// Once the store is gone the process is still live but no longer ready
+	db.Close()
+	var resp map[string]string
+	if code := get(t, srv, "/healthz", &resp); code != http.StatusOK {
+		t.Errorf("/healthz after close = %d %v", code, resp)
+	}
+	if code := get(t, srv, "/readyz", &resp); code != http.StatusServiceUnavailable || resp["status"] != "unavailable" {
+		t.Errorf("/readyz after close = %d %v", code, resp)
+	}
+}
@@ -0,0 +1,87 @@
+package ats
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Kind identifies an applicant tracking system with a public job-board API
+type Kind string
+
+const (
+	Greenhouse      Kind = "greenhouse"
+	Lever           Kind = "lever"
+	Ashby           Kind = "ashby"
+	Workable        Kind = "workable"
+	SmartRecruiters Kind = "smartrecruiters"
+)
+
+// Kinds lists every ATS we recognise. Workable and SmartRecruiters boards are
+// detected by discovery and kept in its mapping, but have no parser yet.
+var Kinds = []Kind{Greenhouse, Lever, Ashby, Workable, SmartRecruiters}
+
+// ParseKind validates an ATS name from config
+func ParseKind(s string) (Kind, error) {
+	for _, k := range Kinds {
+		if strings.EqualFold(s, string(k)) {
+			return k, nil
+		}
+	}
+	return "", fmt.Errorf("unknown ATS %q (want greenhouse, lever, ashby, workable or smartrecruiters)", s)
+}
+
+// Board is one company's job board on an ATS, e.g. {greenhouse, "stripe", "Stripe"}
+type Board struct {
+	ATS     Kind   `json:"ats"`
+	Token   string `json:"token"`             // the company's slug in the ATS's URLs
+	Company string `json:"company,omitempty"` // display name; the token is used when empty
+}
+
+// Name is the company name to put on jobs from this board
+func (b Board) Name() string {
+	if b.Company != "" {
+		return b.Company
+	}
+	return b.Token
+}
+
+// Key identifies the board regardless of display name
+func (b Board) Key() string {
+	return string(b.ATS) + "/" + strings.ToLower(b.Token)
+}
+
+// LoadBoards reads a JSON array of boards
+func LoadBoards(path string) ([]Board, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ATS boards: %v", err)
+	}
+	var boards []Board
+	if err := json.Unmarshal(data, &boards); err != nil {
+		return nil, fmt.Errorf("failed to decode ATS boards: %v", err)
+	}
+	for i, b := range boards {
+		kind, err := ParseKind(string(b.ATS))
+		if err != nil {
+			return nil, fmt.Errorf("%s: board %d: %v", path, i+1, err)
+		}
+		if b.Token == "" {
+			return nil, fmt.Errorf("%s: board %d has no token", path, i+1)
+		}
+		boards[i].ATS = kind
+	}
+	return boards, nil
+}
+
+// Filter returns the boards hosted on kind
+func Filter(boards []Board, kind Kind) []Board {
+	var out []Board
+	for _, b := range boards {
+		if b.ATS == kind {
+			out = append(out, b)
+		}
+	}
+	return out
+}
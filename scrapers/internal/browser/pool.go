@@ -0,0 +1,189 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/chromedp/cdproto/inspector"
+	"github.com/chromedp/chromedp"
+)
+
+// DefaultOptions launch a headless Chrome suitable for containers
+var DefaultOptions = append(chromedp.DefaultExecAllocatorOptions[:],
+	chromedp.Flag("headless", true),
+	chromedp.Flag("disable-gpu", true),
+	chromedp.Flag("no-sandbox", true),
+)
+
+// Pool shares one Chrome process between headless parsers. Each Run gets its
+// own tab; at most MaxTabs run at once and the rest wait their turn.
+type Pool struct {
+	MaxTabs int
+
+	opts []chromedp.ExecAllocatorOption
+	sem  chan struct{}
+
+	mu            sync.Mutex
+	browserCtx    context.Context // nil until the first Run, or after a crash
+	cancelAlloc   context.CancelFunc
+	cancelBrowser context.CancelFunc
+}
+
+// New returns a pool that starts Chrome lazily on first use
+func New(maxTabs int, opts ...chromedp.ExecAllocatorOption) *Pool {
+	if maxTabs < 1 {
+		maxTabs = 1
+	}
+	if len(opts) == 0 {
+		opts = DefaultOptions
+	}
+	return &Pool{MaxTabs: maxTabs, opts: opts, sem: make(chan struct{}, maxTabs)}
+}
+
+// Run executes actions in a fresh tab. If Chrome or the tab crashed, the
+// browser is restarted as needed and the actions are retried once.
+func (p *Pool) Run(ctx context.Context, actions ...chromedp.Action) error {
+	select {
+	case p.sem <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-p.sem }()
+
+	used, err := p.runTab(ctx, actions)
+	if err == nil || used == nil || ctx.Err() != nil {
+		return err // done, Chrome failed to start, or the caller gave up
+	}
+	switch {
+	case errors.Is(err, errTabCrashed):
+		fmt.Println("💥 Headless tab crashed, retrying")
+	case used.Err() != nil || errors.Is(err, context.Canceled):
+		// The tab was torn down under us: Chrome itself has gone away
+		fmt.Printf("💥 Headless browser crashed (%v), restarting\n", err)
+		p.restart(used)
+	default:
+		return err
+	}
+	_, err = p.runTab(ctx, actions)
+	return err
+}
+
+// errTabCrashed is reported when Chrome signals that the page's renderer died
+var errTabCrashed = errors.New("tab crashed")
+
+// runTab runs actions in a new tab and returns the browser it used
+func (p *Pool) runTab(ctx context.Context, actions []chromedp.Action) (context.Context, error) {
+	browserCtx, err := p.browser()
+	if err != nil {
+		return nil, err
+	}
+
+	tabCtx, cancel := chromedp.NewContext(browserCtx)
+	defer cancel()
+	// Tabs hang off the browser, so the caller's deadline has to be carried over by hand
+	stop := context.AfterFunc(ctx, cancel)
+	defer stop()
+
+	var tabCrashed atomic.Bool
+	chromedp.ListenTarget(tabCtx, func(ev interface{}) {
+		if _, ok := ev.(*inspector.EventTargetCrashed); ok {
+			tabCrashed.Store(true)
+			cancel()
+		}
+	})
+
+	err = chromedp.Run(tabCtx, actions...)
+	if tabCrashed.Load() {
+		return browserCtx, errTabCrashed
+	}
+	if err != nil && ctx.Err() != nil {
+		return browserCtx, ctx.Err()
+	}
+	return browserCtx, err
+}
+
+// browser returns the shared browser context, starting Chrome if needed
+func (p *Pool) browser() (context.Context, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.browserCtx != nil && p.browserCtx.Err() == nil {
+		return p.browserCtx, nil
+	}
+	p.shutdownLocked()
+
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(context.Background(), p.opts...)
+	browserCtx, cancelBrowser := chromedp.NewContext(allocCtx)
+	// Running with no actions launches the process and opens the first target
+	if err := chromedp.Run(browserCtx); err != nil {
+		cancelBrowser()
+		cancelAlloc()
+		return nil, fmt.Errorf("failed to start headless browser: %v", err)
+	}
+	p.browserCtx, p.cancelAlloc, p.cancelBrowser = browserCtx, cancelAlloc, cancelBrowser
+	return browserCtx, nil
+}
+
+// restart shuts down the browser that failed, unless another tab already replaced it
+func (p *Pool) restart(failed context.Context) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if failed == nil || p.browserCtx == failed {
+		p.shutdownLocked()
+	}
+}
+
+// Close stops Chrome; the pool starts a new one if it is used again
+func (p *Pool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.shutdownLocked()
+}
+
+func (p *Pool) shutdownLocked() {
+	if p.cancelBrowser != nil {
+		p.cancelBrowser()
+		p.cancelAlloc()
+	}
+	p.browserCtx, p.cancelAlloc, p.cancelBrowser = nil, nil, nil
+}
+
+// Available reports whether a Chrome/Chromium binary can be found
+func Available() bool {
+	for _, name := range []string{"google-chrome", "google-chrome-stable", "chromium", "chromium-browser", "headless-shell", "chrome"} {
+		if _, err := exec.LookPath(name); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	defaultOnce sync.Once
+	defaultPool *Pool
+)
+
+// Default returns the process-wide pool, sized by BROWSER_MAX_TABS (default 2)
+func Default() *Pool {
+	defaultOnce.Do(func() {
+		tabs := 2
+		if n, err := strconv.Atoi(os.Getenv("BROWSER_MAX_TABS")); err == nil && n > 0 {
+			tabs = n
+		}
+		defaultPool = New(tabs)
+	})
+	return defaultPool
+}
+
+// Shutdown closes the default pool's browser, if one was ever started
+func Shutdown() {
+	if defaultPool != nil {
+		defaultPool.Close()
+	}
+}
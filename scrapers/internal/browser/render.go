@@ -0,0 +1,57 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/groot34/job-aggregator/scraper/internal/fixtures"
+	"github.com/groot34/job-aggregator/scraper/internal/httpcache"
+)
+
+// Render navigates to url, runs the wait actions and returns the rendered DOM.
+// Renders go through the response cache and the fixture recorder like plain
+// fetches do, and navigation is redirected to the replay server when active.
+func (p *Pool) Render(ctx context.Context, url string, wait ...chromedp.Action) (string, error) {
+	cache := httpcache.Default()
+	if cache != nil && cache.Mode.Reads() {
+		if e, ok := cache.Get(httpcache.MethodRender, url); ok && e.Fresh(cache.TTL, time.Now()) {
+			fmt.Printf("💾 Cache hit %s %s\n", httpcache.MethodRender, url)
+			return string(e.Body), nil
+		}
+	}
+
+	var html string
+	actions := append([]chromedp.Action{chromedp.Navigate(fixtures.URL(url))}, wait...)
+	actions = append(actions, chromedp.OuterHTML("html", &html, chromedp.ByQuery))
+	if err := p.Run(ctx, actions...); err != nil {
+		return "", err
+	}
+
+	if cache != nil && cache.Mode.Writes() {
+		if err := cache.Put(&httpcache.Entry{
+			Method:     httpcache.MethodRender,
+			URL:        url,
+			StatusCode: 200,
+			Body:       []byte(html),
+		}); err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+		}
+	}
+	if rec := fixtures.ActiveRecorder(); rec != nil {
+		if err := rec.Record(httpcache.MethodRender, url, "text/html; charset=utf-8", 200, []byte(html)); err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+		}
+	}
+	return html, nil
+}
+
+// Evaluate loads html into a blank tab and evaluates js against it
+func (p *Pool) Evaluate(ctx context.Context, html, js string, res interface{}) error {
+	return p.Run(ctx,
+		chromedp.Navigate("about:blank"),
+		SetDocumentContent(html),
+		chromedp.Evaluate(js, res),
+	)
+}
@@ -0,0 +1,115 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+// pollInterval is how often the wait helpers re-check the page
+const pollInterval = 100 * time.Millisecond
+
+// networkIdleJS is true once the document has loaded and no new resource has
+// finished loading for idleMs. Requests still in flight only show up in the
+// performance timeline when they finish, so a quiet timeline means a quiet network.
+const networkIdleJS = `(idleMs) => {
+	const n = performance.getEntriesByType('resource').length;
+	const now = performance.now();
+	const s = window.__scraperIdle || (window.__scraperIdle = {n: -1, since: now});
+	if (n !== s.n) { s.n = n; s.since = now; }
+	return document.readyState === 'complete' && now - s.since >= idleMs;
+}`
+
+// WaitNetworkIdle waits until the page has stopped loading resources for idle.
+// Use it instead of a fixed sleep after navigating to a client-rendered page.
+func WaitNetworkIdle(idle time.Duration) chromedp.Action {
+	return chromedp.PollFunction(networkIdleJS, nil,
+		chromedp.WithPollingArgs(idle.Milliseconds()),
+		chromedp.WithPollingInterval(pollInterval),
+		chromedp.WithPollingTimeout(0), // bounded by the caller's context instead
+	)
+}
+
+// WaitSelectorCount waits until at least min elements match selector
+func WaitSelectorCount(selector string, min int) chromedp.Action {
+	return chromedp.PollFunction(`(sel, min) => document.querySelectorAll(sel).length >= min`, nil,
+		chromedp.WithPollingArgs(selector, min),
+		chromedp.WithPollingInterval(pollInterval),
+		chromedp.WithPollingTimeout(0),
+	)
+}
+
+// SetDocumentContent replaces the current page's DOM with html, which lets a
+// cached render be re-parsed without touching the network
+func SetDocumentContent(html string) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		tree, err := page.GetFrameTree().Do(ctx)
+		if err != nil {
+			return err
+		}
+		return page.SetDocumentContent(tree.Frame.ID, html).Do(ctx)
+	})
+}
+
+// loadMoreJS clicks a visible "load more" / "show more" button if there is
+// one, otherwise scrolls to the bottom to trigger infinite scroll
+const loadMoreJS = `(() => {
+	const button = Array.from(document.querySelectorAll('button, a[role="button"], [class*="load-more"], [class*="loadMore"]'))
+		.find(el => /^\s*(load|show|see|view) more/i.test(el.textContent) && el.offsetParent !== null);
+	if (button) {
+		button.click();
+		return true;
+	}
+	window.scrollTo(0, document.body.scrollHeight);
+	return false;
+})()`
+
+// LoadMore scrolls (or clicks "load more") until no new elements matching
+// itemSelector appear within settle, or maxRounds rounds have run
+func LoadMore(itemSelector string, maxRounds int, settle time.Duration) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		count := func() (int, error) {
+			var n int
+			err := chromedp.Evaluate(`document.querySelectorAll(`+jsString(itemSelector)+`).length`, &n).Do(ctx)
+			return n, err
+		}
+
+		before, err := count()
+		if err != nil {
+			return err
+		}
+		for round := 1; round <= maxRounds; round++ {
+			if err := chromedp.Evaluate(loadMoreJS, nil).Do(ctx); err != nil {
+				return err
+			}
+			err := chromedp.PollFunction(`(sel, n) => document.querySelectorAll(sel).length > n`, nil,
+				chromedp.WithPollingArgs(itemSelector, before),
+				chromedp.WithPollingInterval(pollInterval),
+				chromedp.WithPollingTimeout(settle),
+			).Do(ctx)
+			if errors.Is(err, chromedp.ErrPollingTimeout) {
+				fmt.Printf("📜 Loaded %d items after %d rounds\n", before, round-1)
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if before, err = count(); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("📜 Stopped loading more at the cap of %d rounds (%d items)\n", maxRounds, before)
+		return nil
+	})
+}
+
+// jsString quotes s as a JavaScript string literal
+func jsString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
@@ -0,0 +1,254 @@
+package company
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+)
+
+// Company is the enriched record jobs point at through Job.CompanyID.
+// Records are created as new names are seen; aliases and the profile fields
+// can be curated by hand in the file and survive later runs.
+type Company struct {
+	ID        string    `json:"id"` // stable slug, e.g. "acme"
+	Name      string    `json:"name"`
+	Aliases   []string  `json:"aliases,omitempty"` // other names that resolve here, e.g. "Acme Robotics"
+	Domain    string    `json:"domain,omitempty"`
+	Size      string    `json:"size,omitempty"`  // employee range, e.g. "11-50"
+	Stage     string    `json:"stage,omitempty"` // funding stage, e.g. "Seed", "Series B", "Public"
+	HQ        string    `json:"hq,omitempty"`
+	YCBatch   string    `json:"ycBatch,omitempty"`
+	Sources   []string  `json:"sources,omitempty"` // sources the company has been scraped from
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+// Directory resolves company names to records and persists them as JSON
+type Directory struct {
+	path    string
+	mu      sync.Mutex
+	records map[string]*Company // by ID
+	index   map[string]string   // name key or domain -> ID
+}
+
+// Path returns COMPANIES_PATH, defaulting to data/companies.json
+func Path() string {
+	if p := os.Getenv("COMPANIES_PATH"); p != "" {
+		return p
+	}
+	return "data/companies.json"
+}
+
+// Load reads the directory from path. A missing file yields an empty directory.
+func Load(path string) (*Directory, error) {
+	d := &Directory{path: path, records: make(map[string]*Company), index: make(map[string]string)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return d, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read companies: %v", err)
+	}
+
+	var records []*Company
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to decode companies: %v", err)
+	}
+	for _, c := range records {
+		if c.ID == "" {
+			c.ID = d.newID(c.Name)
+		}
+		d.records[c.ID] = c
+		d.indexRecord(c)
+	}
+	return d, nil
+}
+
+// Save writes the directory back to the path it was loaded from
+func (d *Directory) Save() error {
+	data, err := json.MarshalIndent(d.Companies(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode companies: %v", err)
+	}
+	if dir := filepath.Dir(d.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create companies dir: %v", err)
+		}
+	}
+	if err := os.WriteFile(d.path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write companies: %v", err)
+	}
+	return nil
+}
+
+// Companies returns a snapshot of every record, ordered by ID
+func (d *Directory) Companies() []Company {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	out := make([]Company, 0, len(d.records))
+	for _, c := range d.records {
+		out = append(out, *c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// Get returns the record with id
+func (d *Directory) Get(id string) (Company, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	c, ok := d.records[id]
+	if !ok {
+		return Company{}, false
+	}
+	return *c, true
+}
+
+// Lookup finds the record a raw name resolves to, without creating one
+func (d *Directory) Lookup(raw string) (Company, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	n := Normalize(raw)
+	if n.Key == "" {
+		return Company{}, false
+	}
+	id, ok := d.index[n.Key]
+	if !ok {
+		return Company{}, false
+	}
+	return *d.records[id], true
+}
+
+// Apply resolves a job's company, creating the record on first sight, and
+// rewrites the job to the canonical name and the record's ID. Jobs whose
+// company is a placeholder such as "Unknown" are left alone.
+func (d *Directory) Apply(j *models.Job, now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	n := Normalize(j.Company)
+	if n.Key == "" {
+		return
+	}
+
+	c := d.resolve(n, now)
+	// Remember spelling variants so the file shows what was merged
+	if !strings.EqualFold(n.Display, c.Name) && !contains(c.Aliases, n.Display) {
+		c.Aliases = append(c.Aliases, n.Display)
+	}
+	if c.YCBatch == "" {
+		c.YCBatch = n.Batch
+	}
+	if c.YCBatch == "" {
+		c.YCBatch = batchFromTags(j.Tags)
+	}
+	if !contains(c.Sources, j.Source) && j.Source != "" {
+		c.Sources = append(c.Sources, j.Source)
+		sort.Strings(c.Sources)
+	}
+	c.LastSeen = now
+
+	j.Company = c.Name
+	j.CompanyID = c.ID
+}
+
+// SetDomain records a company's website domain when it has none yet
+func (d *Directory) SetDomain(raw, website string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	domain := Domain(website)
+	n := Normalize(raw)
+	id, ok := d.index[n.Key]
+	if !ok || domain == "" {
+		return
+	}
+	if c := d.records[id]; c.Domain == "" {
+		c.Domain = domain
+		d.index[domain] = id
+	}
+}
+
+// resolve finds or creates the record for n; callers hold d.mu
+func (d *Directory) resolve(n Name, now time.Time) *Company {
+	if id, ok := d.index[n.Key]; ok {
+		return d.records[id]
+	}
+	c := &Company{ID: d.newID(n.Display), Name: n.Display, FirstSeen: now}
+	d.records[c.ID] = c
+	d.indexRecord(c)
+	return c
+}
+
+func (d *Directory) indexRecord(c *Company) {
+	for _, name := range append([]string{c.Name}, c.Aliases...) {
+		if key := Normalize(name).Key; key != "" {
+			d.index[key] = c.ID
+		}
+	}
+	if c.Domain != "" {
+		d.index[Domain(c.Domain)] = c.ID
+	}
+}
+
+// newID slugs name, numbering it when the slug is already taken
+func (d *Directory) newID(name string) string {
+	base := strings.Trim(nonAlnum.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if base == "" {
+		base = "company"
+	}
+	id := base
+	for i := 2; d.records[id] != nil; i++ {
+		id = base + "-" + strconv.Itoa(i)
+	}
+	return id
+}
+
+// Domain reduces a URL or host to its bare host, e.g. "acme.dev"
+func Domain(s string) string {
+	s = strings.TrimSpace(strings.ToLower(s))
+	if s == "" {
+		return ""
+	}
+	if !strings.Contains(s, "://") {
+		s = "https://" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(u.Hostname(), "www.")
+}
+
+// batchFromTags reads the "YC-W21" tag the YC parser adds
+func batchFromTags(tags []string) string {
+	for _, t := range tags {
+		if strings.HasPrefix(t, "YC-") {
+			return strings.TrimPrefix(t, "YC-")
+		}
+	}
+	return ""
+}
+
+// contains reports whether list holds s, ignoring case
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package company
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Name is a raw company name split into its parts
+type Name struct {
+	Display string // cleaned name for showing, e.g. "Acme Technologies"
+	Key     string // matching key, e.g. "acme"; empty for placeholders like "Unknown"
+	Batch   string // YC batch, e.g. "W21", when the raw name carried one
+}
+
+// Names scrapers use when they could not find the company
+var placeholders = map[string]bool{"unknown": true, "n/a": true, "na": true, "-": true, "confidential": true, "stealth": true}
+
+// ycBatch matches "(W21)", "(YC S20)", "[YC W2021]", "YC F24" and "(Summer 2021)" style suffixes
+var ycBatch = regexp.MustCompile(`(?i)\s*[(\[]\s*(?:yc\s*)?([wsfx])\s*'?(\d{2}|\d{4})\s*[)\]]\s*$|\s+yc\s*([wsfx])\s*'?(\d{2}|\d{4})\s*$|\s*[(\[]\s*(?:yc\s*)?(winter|summer|fall|spring)\s*(\d{4})\s*[)\]]\s*$`)
+
+var seasons = map[string]string{"winter": "W", "summer": "S", "fall": "F", "spring": "X"}
+
+// Legal-entity suffixes, longest first so "Pvt Ltd" goes before "Ltd"
+var legalSuffixes = []string{
+	"private limited", "pvt. ltd.", "pvt. ltd", "pvt ltd.", "pvt ltd", "pte. ltd.", "pte ltd",
+	"incorporated", "corporation", "limited", "gmbh", "l.l.c.", "llc", "llp", "plc", "inc.", "inc",
+	"ltd.", "ltd", "corp.", "corp", "co.", "s.a.", "sas", "b.v.", "bv", "ag", "oy", "ab", "pvt",
+}
+
+// Descriptor words dropped from the matching key only, so "ACME Technologies"
+// and "Acme" resolve to the same company while keeping their display names.
+// Words that often name a separate entity, such as "Labs", "Software", "India"
+// or "Group", are deliberately not here: "Acme India" is not "Acme".
+var descriptors = map[string]bool{
+	"technologies": true, "technology": true, "tech": true, "solutions": true, "systems": true,
+	"services": true, "company": true, "hq": true, "the": true, "and": true,
+}
+
+var nonAlnum = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Normalize cleans a scraped company name: whitespace, YC batch suffixes and
+// legal-entity suffixes are stripped, and a key is derived for matching aliases
+func Normalize(raw string) Name {
+	s := strings.Join(strings.Fields(raw), " ")
+	if placeholders[strings.ToLower(s)] {
+		return Name{}
+	}
+
+	var n Name
+	if m := ycBatch.FindStringSubmatch(s); m != nil {
+		n.Batch = batch(m)
+		s = strings.TrimSpace(s[:len(s)-len(m[0])])
+	}
+
+	for stripped := true; stripped; {
+		stripped = false
+		lower := strings.ToLower(s)
+		for _, suffix := range legalSuffixes {
+			if strings.HasSuffix(lower, " "+suffix) || strings.HasSuffix(lower, ","+suffix) {
+				s = strings.TrimRight(s[:len(s)-len(suffix)], " ,")
+				stripped = true
+				break
+			}
+		}
+	}
+
+	n.Display = s
+	n.Key = Key(s)
+	return n
+}
+
+// Key is the matching key for an already cleaned name: lowercase, alphanumeric,
+// without descriptor words unless the name is nothing but descriptors
+func Key(name string) string {
+	words := strings.Fields(nonAlnum.ReplaceAllString(strings.ToLower(name), " "))
+	var kept []string
+	for _, w := range words {
+		if !descriptors[w] {
+			kept = append(kept, w)
+		}
+	}
+	if len(kept) == 0 {
+		kept = words
+	}
+	return strings.Join(kept, "")
+}
+
+// batch turns a ycBatch match into the short form YC uses, e.g. "W21"
+func batch(m []string) string {
+	season, year := m[1], m[2]
+	switch {
+	case m[3] != "":
+		season, year = m[3], m[4]
+	case m[5] != "":
+		season, year = seasons[strings.ToLower(m[5])], m[6]
+	}
+	if len(year) == 4 {
+		year = year[2:]
+	}
+	return strings.ToUpper(season) + year
+}
@@ -0,0 +1,77 @@
+package company
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+)
+
+func TestNormalize(t *testing.T) {
+	for _, tc := range []struct {
+		raw  string
+		want Name
+	}{
+		// YC batch suffixes in their various spellings
+		{"Acme (W21)", Name{Display: "Acme", Key: "acme", Batch: "W21"}},
+		{"Acme (YC S20)", Name{Display: "Acme", Key: "acme", Batch: "S20"}},
+		{"Acme [YC W2021]", Name{Display: "Acme", Key: "acme", Batch: "W21"}},
+		{"Acme YC F24", Name{Display: "Acme", Key: "acme", Batch: "F24"}},
+		{"Acme (Summer 2021)", Name{Display: "Acme", Key: "acme", Batch: "S21"}},
+		{"Acme (yc x'25)", Name{Display: "Acme", Key: "acme", Batch: "X25"}},
+		{"W21 Capital", Name{Display: "W21 Capital", Key: "w21capital"}},
+
+		// Legal suffixes, stacked and comma-separated
+		{"Acme Pvt. Ltd.", Name{Display: "Acme", Key: "acme"}},
+		{"Acme Private Limited", Name{Display: "Acme", Key: "acme"}},
+		{"Acme, Inc.", Name{Display: "Acme", Key: "acme"}},
+		{"Acme Holdings LLC", Name{Display: "Acme Holdings", Key: "acmeholdings"}},
+		{"Acme GmbH & Co. KG", Name{Display: "Acme GmbH & Co. KG", Key: "acmegmbhcokg"}},
+		{"Acme Corp Ltd", Name{Display: "Acme", Key: "acme"}},
+		{"Incorporated", Name{Display: "Incorporated", Key: "incorporated"}},
+
+		// Descriptors only leave the key, and only when something else remains
+		{"  ACME   Technologies ", Name{Display: "ACME Technologies", Key: "acme"}},
+		{"The Acme Company", Name{Display: "The Acme Company", Key: "acme"}},
+		{"Tech Solutions", Name{Display: "Tech Solutions", Key: "techsolutions"}},
+		{"Acme Labs", Name{Display: "Acme Labs", Key: "acmelabs"}},
+		{"Acme Software", Name{Display: "Acme Software", Key: "acmesoftware"}},
+		{"Acme India Pvt Ltd", Name{Display: "Acme India", Key: "acmeindia"}},
+		{"Acme Global Group", Name{Display: "Acme Global Group", Key: "acmeglobalgroup"}},
+
+		// Placeholders have no key
+		{"Unknown", Name{}},
+		{" N/A ", Name{}},
+		{"Confidential", Name{}},
+	} {
+		if got := Normalize(tc.raw); got != tc.want {
+			t.Errorf("Normalize(%q) = %+v, want %+v", tc.raw, got, tc.want)
+		}
+	}
+}
+
+func TestDirectoryKeepsDistinctEntities(t *testing.T) {
+	d, err := Load(filepath.Join(t.TempDir(), "companies.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	ids := make(map[string]string)
+	for _, raw := range []string{"Acme", "ACME Technologies Pvt Ltd", "Acme Labs", "Acme Software", "Acme India", "Acme (W21)"} {
+		j := models.Job{Company: raw}
+		d.Apply(&j, now)
+		ids[raw] = j.CompanyID
+	}
+	for raw, want := range map[string]string{
+		"Acme": "acme", "ACME Technologies Pvt Ltd": "acme", "Acme (W21)": "acme",
+		"Acme Labs": "acme-labs", "Acme Software": "acme-software", "Acme India": "acme-india",
+	} {
+		if ids[raw] != want {
+			t.Errorf("%q resolved to %q, want %q", raw, ids[raw], want)
+		}
+	}
+	if c, _ := d.Get("acme"); c.YCBatch != "W21" || !contains(c.Aliases, "ACME Technologies") {
+		t.Errorf("acme = %+v, want the W21 batch and the Technologies alias", c)
+	}
+}
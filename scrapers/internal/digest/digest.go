@@ -0,0 +1,196 @@
+package digest
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+	"github.com/groot34/job-aggregator/scraper/internal/search"
+)
+
+//go:embed templates/*
+var templateFS embed.FS
+
+var (
+	htmlTmpl = htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/digest.html.tmpl"))
+	textTmpl = texttemplate.Must(texttemplate.ParseFS(templateFS, "templates/digest.txt.tmpl"))
+)
+
+// Subscription sends the listed saved searches to one recipient on a schedule
+type Subscription struct {
+	To       string   `json:"to"`
+	Searches []string `json:"searches"` // saved search names or slugs
+	Period   string   `json:"period"`   // "daily" or "weekly"
+}
+
+// Window is how far back a digest of the given period looks for new jobs
+func Window(period string) (time.Duration, error) {
+	switch period {
+	case "daily":
+		return 24 * time.Hour, nil
+	case "weekly":
+		return 7 * 24 * time.Hour, nil
+	default:
+		return 0, fmt.Errorf("unknown digest period %q", period)
+	}
+}
+
+// LoadSubscriptions reads digest subscriptions from a JSON file containing an array
+func LoadSubscriptions(path string) ([]Subscription, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read digest subscriptions: %v", err)
+	}
+
+	var subs []Subscription
+	if err := json.Unmarshal(data, &subs); err != nil {
+		return nil, fmt.Errorf("failed to decode digest subscriptions: %v", err)
+	}
+	for i, s := range subs {
+		if s.To == "" {
+			return nil, fmt.Errorf("digest subscription #%d has no recipient", i+1)
+		}
+		if _, err := Window(s.Period); err != nil {
+			return nil, err
+		}
+	}
+	return subs, nil
+}
+
+// Digest is the rendered content of one email
+type Digest struct {
+	To      string
+	Subject string
+	Period  string
+	Since   time.Time
+	Total   int
+	HTML    string
+	Text    string
+}
+
+// Section is one saved search inside a digest
+type Section struct {
+	Search     string
+	Jobs       []Item
+	TopSkills  []SkillCount
+	Highlights []string // the skills the search asked for
+}
+
+// Item is a job prepared for the templates
+type Item struct {
+	models.Job
+	Highlighted []string // skills the search asked for
+	OtherSkills []string
+}
+
+// SkillCount is how many jobs in a section mention a skill
+type SkillCount struct {
+	Skill string
+	Count int
+}
+
+// topSkillsLimit caps the skill summary at the top of each section
+const topSkillsLimit = 5
+
+// Build groups newJobs into one section per subscribed search and renders both bodies.
+// ok is false when none of the searches matched anything, so no email should be sent.
+func Build(sub Subscription, searches []search.Saved, newJobs []models.Job, since time.Time) (d Digest, ok bool, err error) {
+	d = Digest{To: sub.To, Period: sub.Period, Since: since}
+
+	var sections []Section
+	// A job matching several searches is listed in each section but counted once
+	seen := make(map[string]bool)
+	for _, name := range sub.Searches {
+		s, found := search.Find(searches, name)
+		if !found {
+			return d, false, fmt.Errorf("digest for %s references unknown saved search %q", sub.To, name)
+		}
+		matched := s.Filter(newJobs)
+		if len(matched) == 0 {
+			continue
+		}
+		sections = append(sections, buildSection(s, matched))
+		for _, j := range matched {
+			if !seen[j.ID] {
+				seen[j.ID] = true
+				d.Total++
+			}
+		}
+	}
+	if d.Total == 0 {
+		return d, false, nil
+	}
+
+	d.Subject = fmt.Sprintf("Your %s job digest: %s", sub.Period, newJobsCount(d.Total))
+	data := struct {
+		Digest
+		Sections []Section
+	}{d, sections}
+
+	var html, text bytes.Buffer
+	if err := htmlTmpl.Execute(&html, data); err != nil {
+		return d, false, fmt.Errorf("failed to render digest html: %v", err)
+	}
+	if err := textTmpl.Execute(&text, data); err != nil {
+		return d, false, fmt.Errorf("failed to render digest text: %v", err)
+	}
+	d.HTML, d.Text = html.String(), text.String()
+	return d, true, nil
+}
+
+// newJobsCount writes n as "1 new job" or "n new jobs"
+func newJobsCount(n int) string {
+	if n == 1 {
+		return "1 new job"
+	}
+	return fmt.Sprintf("%d new jobs", n)
+}
+
+func buildSection(s search.Saved, jobs []models.Job) Section {
+	sec := Section{Search: s.Name, Highlights: s.Skills}
+	counts := make(map[string]int)
+
+	for _, j := range jobs {
+		item := Item{Job: j}
+		for _, t := range j.Tags {
+			counts[t]++
+			if containsFold(s.Skills, t) {
+				item.Highlighted = append(item.Highlighted, t)
+			} else {
+				item.OtherSkills = append(item.OtherSkills, t)
+			}
+		}
+		sec.Jobs = append(sec.Jobs, item)
+	}
+
+	for skill, n := range counts {
+		sec.TopSkills = append(sec.TopSkills, SkillCount{Skill: skill, Count: n})
+	}
+	sort.Slice(sec.TopSkills, func(i, j int) bool {
+		if sec.TopSkills[i].Count != sec.TopSkills[j].Count {
+			return sec.TopSkills[i].Count > sec.TopSkills[j].Count
+		}
+		return sec.TopSkills[i].Skill < sec.TopSkills[j].Skill
+	})
+	if len(sec.TopSkills) > topSkillsLimit {
+		sec.TopSkills = sec.TopSkills[:topSkillsLimit]
+	}
+	return sec
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
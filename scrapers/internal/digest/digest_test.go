@@ -0,0 +1,45 @@
+package digest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+	"github.com/groot34/job-aggregator/scraper/internal/search"
+)
+
+func TestBuildCountsEachJobOnce(t *testing.T) {
+	searches := []search.Saved{
+		{Name: "Go jobs", Skills: []string{"go"}},
+		{Name: "Postgres jobs", Skills: []string{"postgres"}},
+	}
+	jobs := []models.Job{
+		{ID: "1", Title: "Go Engineer", Company: "Acme", Tags: []string{"Go", "Postgres"}},
+		{ID: "2", Title: "DBA", Company: "Globex", Tags: []string{"Postgres"}},
+	}
+	sub := Subscription{To: "dev@example.com", Searches: []string{"go-jobs", "postgres-jobs"}, Period: "weekly"}
+
+	d, ok, err := Build(sub, searches, jobs, time.Now().AddDate(0, 0, -7))
+	if err != nil || !ok {
+		t.Fatalf("Build = %v, %v", ok, err)
+	}
+	// Job 1 is listed under both searches but is still one new job
+	if d.Total != 2 || d.Subject != "Your weekly job digest: 2 new jobs" {
+		t.Errorf("Total = %d, Subject = %q; want 2 new jobs", d.Total, d.Subject)
+	}
+	if strings.Count(d.Text, "Go Engineer at Acme") != 2 {
+		t.Errorf("text should list job 1 in both sections:\n%s", d.Text)
+	}
+	if !strings.HasPrefix(d.Text, "2 new jobs since") {
+		t.Errorf("text headline = %q", strings.SplitN(d.Text, "\n", 2)[0])
+	}
+}
+
+func TestNewJobsCount(t *testing.T) {
+	for n, want := range map[int]string{1: "1 new job", 2: "2 new jobs", 12: "12 new jobs"} {
+		if got := newJobsCount(n); got != want {
+			t.Errorf("newJobsCount(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
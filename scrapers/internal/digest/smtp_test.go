@@ -0,0 +1,152 @@
+package digest
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+	"github.com/groot34/job-aggregator/scraper/internal/search"
+)
+
+// smtpStub accepts one unauthenticated message on a local port and hands
+// back its envelope recipient and raw data
+func smtpStub(t *testing.T) (addr string, received <-chan [2]string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	out := make(chan [2]string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		tc := textproto.NewConn(conn)
+
+		var rcpt string
+		tc.PrintfLine("220 localhost stub")
+		for {
+			line, err := tc.ReadLine()
+			if err != nil {
+				return
+			}
+			switch verb := strings.ToUpper(strings.Fields(line)[0]); verb {
+			case "EHLO", "HELO":
+				tc.PrintfLine("250 localhost")
+			case "RCPT":
+				rcpt = strings.Trim(strings.TrimPrefix(line, "RCPT TO:"), "<>")
+				tc.PrintfLine("250 OK")
+			case "DATA":
+				tc.PrintfLine("354 go ahead")
+				data, err := io.ReadAll(tc.DotReader())
+				if err != nil {
+					return
+				}
+				out <- [2]string{rcpt, string(data)}
+				tc.PrintfLine("250 OK")
+			case "QUIT":
+				tc.PrintfLine("221 bye")
+				return
+			default:
+				tc.PrintfLine("250 OK")
+			}
+		}
+	}()
+	return ln.Addr().String(), out
+}
+
+func TestSend(t *testing.T) {
+	addr, received := smtpStub(t)
+	host, port, _ := net.SplitHostPort(addr)
+
+	searches := []search.Saved{{Name: "Go jobs", Skills: []string{"go"}}}
+	jobs := []models.Job{
+		{ID: "1", Title: "Go Engineer", Company: "Acme & Co", URL: "https://example.com/1", Tags: []string{"Go", "Postgres"}},
+		{ID: "2", Title: "Designer", Company: "Globex", Tags: []string{"Figma"}},
+	}
+	sub := Subscription{To: "dev@example.com", Searches: []string{"go-jobs"}, Period: "daily"}
+	d, ok, err := Build(sub, searches, jobs, time.Now().Add(-24*time.Hour))
+	if err != nil || !ok {
+		t.Fatalf("Build = %v, %v", ok, err)
+	}
+
+	cfg := SMTPConfig{Host: host, Port: port, From: "jobs@example.com"}
+	if err := Send(cfg, d); err != nil {
+		t.Fatal(err)
+	}
+
+	var got [2]string
+	select {
+	case got = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stub received no message")
+	}
+	if got[0] != "dev@example.com" {
+		t.Errorf("envelope recipient = %q", got[0])
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(got[1]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for header, want := range map[string]string{
+		"From":         "jobs@example.com",
+		"To":           "dev@example.com",
+		"MIME-Version": "1.0",
+	} {
+		if v := msg.Header.Get(header); v != want {
+			t.Errorf("%s = %q, want %q", header, v, want)
+		}
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != "Your daily job digest: 1 new job" {
+		t.Errorf("Subject = %q (%v)", subject, err)
+	}
+	if _, err := msg.Header.Date(); err != nil {
+		t.Errorf("Date: %v", err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q (%v)", msg.Header.Get("Content-Type"), err)
+	}
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+
+	// Plaintext first so clients that can render HTML prefer the last part
+	for _, want := range []struct{ contentType, contains string }{
+		{"text/plain; charset=utf-8", "Go Engineer"},
+		{"text/html; charset=utf-8", "Acme &amp; Co"},
+	} {
+		part, err := mr.NextPart()
+		if err != nil {
+			t.Fatalf("reading %s part: %v", want.contentType, err)
+		}
+		if ct := part.Header.Get("Content-Type"); ct != want.contentType {
+			t.Errorf("part Content-Type = %q, want %q", ct, want.contentType)
+		}
+		body, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(body), want.contains) {
+			t.Errorf("%s part lacks %q:\n%s", want.contentType, want.contains, body)
+		}
+		if strings.Contains(string(body), "Designer") {
+			t.Errorf("%s part includes a job outside the subscribed search", want.contentType)
+		}
+	}
+	if _, err := mr.NextPart(); err != io.EOF {
+		t.Errorf("want exactly two parts, next part err = %v", err)
+	}
+}
@@ -0,0 +1,265 @@
+package discovery
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/groot34/job-aggregator/scraper/internal/ats"
+	"github.com/groot34/job-aggregator/scraper/internal/fetch"
+	"github.com/groot34/job-aggregator/scraper/internal/jobposting"
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+)
+
+// boardPatterns match links (and embed scripts) pointing at a company's ATS board;
+// the first group is the board token
+var boardPatterns = []struct {
+	kind ats.Kind
+	re   *regexp.Regexp
+}{
+	{ats.Greenhouse, regexp.MustCompile(`(?i)(?:job-)?boards(?:\.eu)?\.greenhouse\.io/(?:embed/job_board(?:/js)?\?for=)?([a-z0-9_-]+)`)},
+	{ats.Greenhouse, regexp.MustCompile(`(?i)boards-api\.greenhouse\.io/v1/boards/([a-z0-9_-]+)`)},
+	{ats.Lever, regexp.MustCompile(`(?i)jobs\.lever\.co/([a-z0-9_.-]+)`)},
+	{ats.Ashby, regexp.MustCompile(`(?i)jobs\.ashbyhq\.com/([a-z0-9_.%-]+)`)},
+	{ats.Workable, regexp.MustCompile(`(?i)apply\.workable\.com/([a-z0-9_-]+)`)},
+	{ats.SmartRecruiters, regexp.MustCompile(`(?i)(?:jobs|careers)\.smartrecruiters\.com/([a-z0-9_-]+)`)},
+}
+
+// Path segments the patterns above can capture that are never board tokens
+var notTokens = map[string]bool{"embed": true, "api": true, "v1": true, "js": true, "widget": true}
+
+// careersLink matches the text or URL of a link to a company's jobs page
+var careersLink = regexp.MustCompile(`(?i)\b(careers?|jobs|join us|join the team|we're hiring|work with us|open (roles|positions))\b`)
+
+// Hosts linked from company pages that are never the company's own website
+var notWebsites = []string{
+	"ycombinator.com", "wellfound.com", "angel.co", "twitter.com", "x.com", "linkedin.com",
+	"facebook.com", "github.com", "crunchbase.com", "youtube.com", "instagram.com",
+	"medium.com", "google.com", "apple.com",
+}
+
+// CompanyPage returns the aggregator's company page for a job, when its URL has one:
+// YC job URLs live under /companies/<slug>/, Wellfound's under /company/<slug>/
+func CompanyPage(j models.Job) string {
+	u, err := url.Parse(j.URL)
+	if err != nil {
+		return ""
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 || parts[1] == "" {
+		return ""
+	}
+	switch host := strings.TrimPrefix(u.Hostname(), "www."); {
+	case host == "ycombinator.com" && parts[0] == "companies":
+		return "https://www.ycombinator.com/companies/" + parts[1]
+	case host == "wellfound.com" && parts[0] == "company":
+		return "https://wellfound.com/company/" + parts[1]
+	}
+	return ""
+}
+
+// Report summarises one discovery pass
+type Report struct {
+	Checked      int
+	Boards       int
+	CareersPages int
+	Failed       int
+}
+
+// Discoverer follows company pages to the companies' own job boards
+type Discoverer struct {
+	store *Store
+	TTL   time.Duration // how long a company's result is trusted before it is checked again
+	Max   int           // companies checked per run; 0 means no limit
+	pages *fetch.Getter
+}
+
+// New returns a discoverer that records into store
+func New(store *Store, ttl time.Duration, max int) *Discoverer {
+	return &Discoverer{
+		store: store,
+		TTL:   ttl,
+		Max:   max,
+		pages: fetch.NewGetter("Discovery"),
+	}
+}
+
+// Run checks the company pages behind jobs that are due for a check
+func (d *Discoverer) Run(jobs []models.Job, now time.Time) Report {
+	var report Report
+	seen := make(map[string]bool)
+	for _, j := range jobs {
+		page := CompanyPage(j)
+		if page == "" || seen[page] || !d.store.Due(page, now, d.TTL) {
+			continue
+		}
+		seen[page] = true
+		if d.Max > 0 && report.Checked+report.Failed >= d.Max {
+			break
+		}
+
+		c, err := d.check(page, j.Company)
+		if err != nil {
+			// Not recorded, so the next run tries again
+			fmt.Printf("❌ Discovery %s: %v\n", page, err)
+			report.Failed++
+			continue
+		}
+		c.CheckedAt = now
+		d.store.Put(c)
+
+		report.Checked++
+		report.Boards += len(c.Boards)
+		if c.CareersURL != "" {
+			report.CareersPages++
+		}
+	}
+	return report
+}
+
+// check visits a company page, then the company's website and careers page
+// until it finds an ATS board or JobPosting data
+func (d *Discoverer) check(page, name string) (Company, error) {
+	c := Company{Name: name, Page: page}
+
+	body, err := d.get(page)
+	if err != nil {
+		return c, err
+	}
+	if c.Boards = detectBoards(body, name); len(c.Boards) > 0 {
+		return c, nil
+	}
+
+	c.Website = website(body, page)
+	if c.Website == "" {
+		return c, nil
+	}
+	// Failures past the company page are the company's, so the result is still recorded
+	site, err := d.get(c.Website)
+	if err != nil {
+		fmt.Printf("⚠️  Discovery %s: %v\n", c.Website, err)
+		return c, nil
+	}
+	if c.Boards = detectBoards(site, name); len(c.Boards) > 0 {
+		return c, nil
+	}
+	if hasPostings(site, c.Website) {
+		c.CareersURL = c.Website
+		return c, nil
+	}
+
+	careers := careersPage(site, c.Website)
+	if careers == "" {
+		return c, nil
+	}
+	careersBody, err := d.get(careers)
+	if err != nil {
+		fmt.Printf("⚠️  Discovery %s: %v\n", careers, err)
+		return c, nil
+	}
+	if c.Boards = detectBoards(careersBody, name); len(c.Boards) > 0 {
+		return c, nil
+	}
+	if hasPostings(careersBody, careers) {
+		c.CareersURL = careers
+	}
+	return c, nil
+}
+
+// get fetches rawURL and returns the raw HTML
+func (d *Discoverer) get(rawURL string) (string, error) {
+	body, err := d.pages.Get(rawURL)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// detectBoards finds every ATS board linked or embedded in a page
+func detectBoards(html, company string) []ats.Board {
+	var boards []ats.Board
+	seen := make(map[string]bool)
+	for _, p := range boardPatterns {
+		for _, m := range p.re.FindAllStringSubmatch(html, -1) {
+			token := strings.TrimRight(m[1], ".-")
+			if token == "" || notTokens[strings.ToLower(token)] {
+				continue
+			}
+			b := ats.Board{ATS: p.kind, Token: token, Company: company}
+			if !seen[b.Key()] {
+				seen[b.Key()] = true
+				boards = append(boards, b)
+			}
+		}
+	}
+	return boards
+}
+
+// website returns the first outbound link on a company page that isn't a
+// social profile or another aggregator
+func website(html, pageURL string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return ""
+	}
+	var found string
+	doc.Find("a[href^='http']").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		u, err := url.Parse(s.AttrOr("href", ""))
+		if err != nil || u.Hostname() == "" || u.Hostname() == hostname(pageURL) {
+			return true
+		}
+		for _, h := range notWebsites {
+			if u.Hostname() == h || strings.HasSuffix(u.Hostname(), "."+h) {
+				return true
+			}
+		}
+		found = u.Scheme + "://" + u.Host + u.Path
+		return false
+	})
+	return found
+}
+
+// careersPage returns the first link on the company's website that looks like its jobs page
+func careersPage(html, siteURL string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return ""
+	}
+	base, err := url.Parse(siteURL)
+	if err != nil {
+		return ""
+	}
+	var found string
+	doc.Find("a[href]").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		href := s.AttrOr("href", "")
+		if !careersLink.MatchString(s.Text()) && !careersLink.MatchString(href) {
+			return true
+		}
+		u, err := base.Parse(href)
+		// Other sites' jobs pages aren't this company's, unless they're its ATS board
+		// (which detectBoards would already have picked up)
+		if err != nil || u.Hostname() != base.Hostname() {
+			return true
+		}
+		u.Fragment = ""
+		found = u.String()
+		return false
+	})
+	return found
+}
+
+func hasPostings(html, pageURL string) bool {
+	postings, err := jobposting.Extract(html, pageURL)
+	return err == nil && len(postings) > 0
+}
+
+func hostname(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
@@ -0,0 +1,63 @@
+package discovery
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/groot34/job-aggregator/scraper/internal/ats"
+)
+
+func TestCheckFollowsBareHostWebsite(t *testing.T) {
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `<html><body><a href="https://boards.greenhouse.io/acme">Open roles</a></body></html>`)
+	}))
+	defer site.Close()
+	// The company page must be on another host than the website, so serve it on localhost
+	bareHost := strings.Replace(site.URL, "127.0.0.1", "localhost", 1)
+
+	page := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/companies/acme" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `<html><body><a href="https://twitter.com/acme">Twitter</a><a href="%s">Website</a></body></html>`, bareHost)
+	}))
+	defer page.Close()
+
+	store, err := Load(filepath.Join(t.TempDir(), "discovery.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := New(store, 0, 0)
+	c, err := d.check(page.URL+"/companies/acme", "Acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Website != bareHost {
+		t.Errorf("website = %q, want %q", c.Website, bareHost)
+	}
+	if len(c.Boards) != 1 || c.Boards[0].ATS != ats.Greenhouse || c.Boards[0].Token != "acme" {
+		t.Errorf("boards = %+v, want the Greenhouse board linked from the bare-host website", c.Boards)
+	}
+}
+
+func TestDetectBoards(t *testing.T) {
+	html := `<script src="https://boards.greenhouse.io/embed/job_board/js?for=globex"></script>
+		<a href="https://jobs.lever.co/initech/">Jobs</a> <a href="https://jobs.lever.co/initech">Again</a>`
+	boards := detectBoards(html, "Globex")
+	var got []string
+	for _, b := range boards {
+		got = append(got, string(b.ATS)+":"+b.Token)
+	}
+	if want := "greenhouse:globex lever:initech"; strings.Join(got, " ") != want {
+		t.Errorf("boards = %v, want %s", got, want)
+	}
+}
@@ -0,0 +1,137 @@
+package discovery
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/ats"
+)
+
+// Company is what discovery learned about one company's own job listings
+type Company struct {
+	Name       string      `json:"name"`
+	Page       string      `json:"page"` // the aggregator's company page we started from
+	Website    string      `json:"website,omitempty"`
+	Boards     []ats.Board `json:"boards,omitempty"`
+	CareersURL string      `json:"careersUrl,omitempty"` // careers page with JobPosting data, when no board was found
+	CheckedAt  time.Time   `json:"checkedAt"`
+}
+
+// Store keeps discovered companies keyed by company page and persists them as JSON
+type Store struct {
+	path      string
+	mu        sync.Mutex
+	companies map[string]*Company
+}
+
+// Path returns DISCOVERY_PATH, defaulting to data/discovery.json
+func Path() string {
+	if p := os.Getenv("DISCOVERY_PATH"); p != "" {
+		return p
+	}
+	return "data/discovery.json"
+}
+
+// Load reads the store from path. A missing file yields an empty store.
+func Load(path string) (*Store, error) {
+	s := &Store{path: path, companies: make(map[string]*Company)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read discovery state: %v", err)
+	}
+
+	var companies []*Company
+	if err := json.Unmarshal(data, &companies); err != nil {
+		return nil, fmt.Errorf("failed to decode discovery state: %v", err)
+	}
+	for _, c := range companies {
+		s.companies[key(c.Page)] = c
+	}
+	return s, nil
+}
+
+// Save writes the store back to the path it was loaded from
+func (s *Store) Save() error {
+	data, err := json.MarshalIndent(s.Companies(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode discovery state: %v", err)
+	}
+	if dir := filepath.Dir(s.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create discovery dir: %v", err)
+		}
+	}
+	if err := os.WriteFile(s.path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write discovery state: %v", err)
+	}
+	return nil
+}
+
+// Due reports whether page has never been checked or was last checked more than ttl ago
+func (s *Store) Due(page string, now time.Time, ttl time.Duration) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.companies[key(page)]
+	return !ok || now.Sub(c.CheckedAt) > ttl
+}
+
+// Put records the result of checking a company, replacing any earlier one
+func (s *Store) Put(c Company) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.companies[key(c.Page)] = &c
+}
+
+// Companies returns a snapshot of every company, ordered by page
+func (s *Store) Companies() []Company {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]Company, 0, len(s.companies))
+	for _, c := range s.companies {
+		out = append(out, *c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Page < out[j].Page })
+	return out
+}
+
+// Boards returns every discovered board, deduplicated by key
+func (s *Store) Boards() []ats.Board {
+	var out []ats.Board
+	seen := make(map[string]bool)
+	for _, c := range s.Companies() {
+		for _, b := range c.Boards {
+			if !seen[b.Key()] {
+				seen[b.Key()] = true
+				out = append(out, b)
+			}
+		}
+	}
+	return out
+}
+
+// CareersPages returns the discovered careers pages that carry JobPosting data
+func (s *Store) CareersPages() []string {
+	var out []string
+	for _, c := range s.Companies() {
+		if c.CareersURL != "" {
+			out = append(out, c.CareersURL)
+		}
+	}
+	return out
+}
+
+func key(page string) string {
+	return strings.ToLower(strings.TrimSuffix(page, "/"))
+}
@@ -0,0 +1,350 @@
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+	"github.com/groot34/job-aggregator/scraper/internal/search"
+)
+
+// Format is one of the supported feed document types
+type Format string
+
+const (
+	FormatRSS  Format = "rss"
+	FormatAtom Format = "atom"
+	FormatJSON Format = "json"
+)
+
+// Formats lists every format in the order they are written to disk
+var Formats = []Format{FormatRSS, FormatAtom, FormatJSON}
+
+// Extension is the file extension used when writing a feed of this format
+func (f Format) Extension() string {
+	switch f {
+	case FormatRSS:
+		return ".rss"
+	case FormatAtom:
+		return ".atom"
+	default:
+		return ".json"
+	}
+}
+
+// ContentType is the MIME type served for this format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatRSS:
+		return "application/rss+xml; charset=utf-8"
+	case FormatAtom:
+		return "application/atom+xml; charset=utf-8"
+	default:
+		return "application/feed+json; charset=utf-8"
+	}
+}
+
+// Meta describes the feed as a whole
+type Meta struct {
+	ID          string // stable identifier, used when there is no FeedURL
+	Title       string
+	Description string
+	Link        string // human-facing page the feed belongs to
+	FeedURL     string // where the feed itself is published, if known
+	Updated     time.Time
+}
+
+// MetaFor builds feed metadata for a saved search
+func MetaFor(s search.Saved, baseURL string) Meta {
+	m := Meta{
+		ID:          "urn:jobfeed:" + s.Slug(),
+		Title:       "Jobs: " + s.Name,
+		Description: "Scraped job listings matching the saved search " + s.Name,
+		Link:        baseURL,
+		Updated:     time.Now(),
+	}
+	if baseURL != "" {
+		m.FeedURL = strings.TrimSuffix(baseURL, "/") + "/feeds/" + s.Slug()
+	}
+	return m
+}
+
+// Write renders jobs as a feed document in the given format
+func Write(w io.Writer, format Format, meta Meta, jobs []models.Job) error {
+	switch format {
+	case FormatRSS:
+		return writeRSS(w, meta, jobs)
+	case FormatAtom:
+		return writeAtom(w, meta, jobs)
+	case FormatJSON:
+		return writeJSONFeed(w, meta, jobs)
+	default:
+		return fmt.Errorf("unknown feed format %q", format)
+	}
+}
+
+// WriteFiles writes every format of the search's feed into dir as <slug>.<ext>
+func WriteFiles(dir string, s search.Saved, baseURL string, jobs []models.Job) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create feed dir: %v", err)
+	}
+
+	matched := s.Filter(jobs)
+	meta := MetaFor(s, baseURL)
+	for _, format := range Formats {
+		path := filepath.Join(dir, s.Slug()+format.Extension())
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create feed file: %v", err)
+		}
+		err = Write(f, format, meta, matched)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %v", path, err)
+		}
+	}
+	return nil
+}
+
+// itemTitle is shared by all formats so readers see the same headline everywhere
+func itemTitle(j models.Job) string {
+	if j.Company == "" {
+		return j.Title
+	}
+	return j.Title + " at " + j.Company
+}
+
+// itemSummary is a short HTML body listing the structured fields of a job
+func itemSummary(j models.Job) string {
+	var b strings.Builder
+	field := func(label, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "<p><strong>%s:</strong> %s</p>", label, html.EscapeString(value))
+		}
+	}
+	field("Company", j.Company)
+	field("Location", j.Location)
+	if j.Remote {
+		field("Remote", "Yes")
+	}
+	field("Salary", j.Salary)
+	field("Skills", strings.Join(j.Tags, ", "))
+	field("Source", j.Source)
+	if j.Description != "" {
+		fmt.Fprintf(&b, "<p>%s</p>", html.EscapeString(j.Description))
+	}
+	return b.String()
+}
+
+// --- RSS 2.0 ---
+
+type rssDoc struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Description string   `xml:"description"`
+	Categories  []string `xml:"category"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+func writeRSS(w io.Writer, meta Meta, jobs []models.Job) error {
+	doc := rssDoc{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:         meta.Title,
+			Link:          meta.Link,
+			Description:   meta.Description,
+			LastBuildDate: meta.Updated.Format(time.RFC1123Z),
+		},
+	}
+	for _, j := range jobs {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       itemTitle(j),
+			Link:        j.URL,
+			GUID:        rssGUID{Value: j.ID},
+			PubDate:     j.PostedAt.Format(time.RFC1123Z),
+			Description: itemSummary(j),
+			Categories:  j.Tags,
+		})
+	}
+	return writeXML(w, doc)
+}
+
+// --- Atom 1.0 ---
+
+type atomDoc struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published"`
+	Links      []atomLink     `xml:"link"`
+	Author     atomAuthor     `xml:"author"`
+	Summary    atomText       `xml:"summary"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+func writeAtom(w io.Writer, meta Meta, jobs []models.Job) error {
+	doc := atomDoc{
+		Title:   meta.Title,
+		ID:      feedID(meta),
+		Updated: meta.Updated.Format(time.RFC3339),
+	}
+	if meta.Link != "" {
+		doc.Links = append(doc.Links, atomLink{Href: meta.Link})
+	}
+	if meta.FeedURL != "" {
+		doc.Links = append(doc.Links, atomLink{Href: meta.FeedURL + "/atom", Rel: "self"})
+	}
+
+	for _, j := range jobs {
+		entry := atomEntry{
+			Title:     itemTitle(j),
+			ID:        "urn:job:" + j.ID,
+			Updated:   j.ScrapedAt.Format(time.RFC3339),
+			Published: j.PostedAt.Format(time.RFC3339),
+			Links:     []atomLink{{Href: j.URL}},
+			Author:    atomAuthor{Name: j.Company},
+			Summary:   atomText{Type: "html", Value: itemSummary(j)},
+		}
+		for _, t := range j.Tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: t})
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return writeXML(w, doc)
+}
+
+// feedID prefers the feed URL; Atom requires an ID even for feeds written to disk
+func feedID(meta Meta) string {
+	if meta.FeedURL != "" {
+		return meta.FeedURL
+	}
+	return meta.ID
+}
+
+func writeXML(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// --- JSON Feed 1.1 ---
+
+type jsonFeedDoc struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url,omitempty"`
+	FeedURL     string         `json:"feed_url,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string           `json:"id"`
+	URL           string           `json:"url,omitempty"`
+	Title         string           `json:"title"`
+	ContentHTML   string           `json:"content_html"`
+	DatePublished string           `json:"date_published"`
+	DateModified  string           `json:"date_modified"`
+	Authors       []jsonFeedAuthor `json:"authors,omitempty"`
+	Tags          []string         `json:"tags,omitempty"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+func writeJSONFeed(w io.Writer, meta Meta, jobs []models.Job) error {
+	doc := jsonFeedDoc{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       meta.Title,
+		HomePageURL: meta.Link,
+		Description: meta.Description,
+		Items:       []jsonFeedItem{},
+	}
+	if meta.FeedURL != "" {
+		doc.FeedURL = meta.FeedURL + "/json"
+	}
+
+	for _, j := range jobs {
+		item := jsonFeedItem{
+			ID:            j.ID,
+			URL:           j.URL,
+			Title:         itemTitle(j),
+			ContentHTML:   itemSummary(j),
+			DatePublished: j.PostedAt.Format(time.RFC3339),
+			DateModified:  j.ScrapedAt.Format(time.RFC3339),
+			Tags:          j.Tags,
+		}
+		if j.Company != "" {
+			item.Authors = []jsonFeedAuthor{{Name: j.Company}}
+		}
+		doc.Items = append(doc.Items, item)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
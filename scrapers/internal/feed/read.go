@@ -0,0 +1,196 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+// Entry is one item of a feed flattened to its fields, keyed by element or
+// property name without namespace ("encoded" for content:encoded). Nested
+// values are keyed by their path, e.g. "author.name"; repeated ones, such as
+// RSS categories, keep every value.
+type Entry map[string][]string
+
+// First returns the first non-empty value of the first key that has one
+func (e Entry) First(keys ...string) string {
+	for _, k := range keys {
+		for _, v := range e[k] {
+			if v = strings.TrimSpace(v); v != "" {
+				return v
+			}
+		}
+	}
+	return ""
+}
+
+// All returns every non-empty value of the first key that has any
+func (e Entry) All(keys ...string) []string {
+	for _, k := range keys {
+		var out []string
+		for _, v := range e[k] {
+			if v = strings.TrimSpace(v); v != "" {
+				out = append(out, v)
+			}
+		}
+		if len(out) > 0 {
+			return out
+		}
+	}
+	return nil
+}
+
+func (e Entry) add(key, value string) {
+	if value = strings.TrimSpace(value); value != "" {
+		e[key] = append(e[key], value)
+	}
+}
+
+// Read parses an RSS, RDF, Atom or JSON Feed document into its entries
+func Read(data []byte) ([]Entry, Format, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		entries, err := readJSON(trimmed)
+		return entries, FormatJSON, err
+	}
+
+	var root xmlNode
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.CharsetReader = charset.NewReaderLabel
+	dec.Strict = false
+	if err := dec.Decode(&root); err != nil {
+		return nil, "", fmt.Errorf("failed to decode feed: %v", err)
+	}
+
+	format := FormatRSS
+	if root.XMLName.Local == "feed" {
+		format = FormatAtom
+	}
+	var entries []Entry
+	root.walk(func(n *xmlNode) bool {
+		if n.XMLName.Local != "item" && n.XMLName.Local != "entry" {
+			return true
+		}
+		e := make(Entry)
+		for i := range n.Nodes {
+			n.Nodes[i].flatten(e, "")
+		}
+		entries = append(entries, e)
+		return false
+	})
+	return entries, format, nil
+}
+
+// xmlNode keeps any XML element generically
+type xmlNode struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Text    string     `xml:",chardata"`
+	Inner   string     `xml:",innerxml"`
+	Nodes   []xmlNode  `xml:",any"`
+}
+
+// walk visits n and its descendants depth-first while visit returns true
+func (n *xmlNode) walk(visit func(*xmlNode) bool) {
+	if !visit(n) {
+		return
+	}
+	for i := range n.Nodes {
+		n.Nodes[i].walk(visit)
+	}
+}
+
+func (n *xmlNode) flatten(e Entry, prefix string) {
+	key := prefix + n.XMLName.Local
+	switch {
+	case n.attr("href") != "":
+		// Atom links: the alternate (or only) link is the entry's page
+		if rel := n.attr("rel"); rel == "" || rel == "alternate" {
+			e.add(key, n.attr("href"))
+		} else {
+			e.add(key+"."+rel, n.attr("href"))
+		}
+	case n.attr("url") != "" && strings.TrimSpace(n.Text) == "":
+		e.add(key, n.attr("url"))
+	case len(n.Nodes) == 0:
+		e.add(key, n.Text)
+	case n.attr("type") == "xhtml":
+		// Inline XHTML content is markup, not nested fields
+		e.add(key, n.Inner)
+	default:
+		for i := range n.Nodes {
+			n.Nodes[i].flatten(e, key+".")
+		}
+	}
+	// Atom categories carry their value in an attribute
+	if term := n.attr("term"); term != "" {
+		e.add(key, term)
+	}
+}
+
+func (n *xmlNode) attr(name string) string {
+	for _, a := range n.Attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func readJSON(data []byte) ([]Entry, error) {
+	var doc struct {
+		Items []map[string]interface{} `json:"items"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON feed: %v", err)
+	}
+	entries := make([]Entry, 0, len(doc.Items))
+	for _, item := range doc.Items {
+		e := make(Entry)
+		for k, v := range item {
+			flattenJSON(e, k, v)
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func flattenJSON(e Entry, key string, v interface{}) {
+	switch v := v.(type) {
+	case string:
+		e.add(key, v)
+	case float64:
+		e.add(key, fmt.Sprint(v))
+	case bool:
+		e.add(key, fmt.Sprint(v))
+	case []interface{}:
+		for _, item := range v {
+			flattenJSON(e, key, item)
+		}
+	case map[string]interface{}:
+		for k, child := range v {
+			flattenJSON(e, key+"."+k, child)
+		}
+	}
+}
+
+// ParseDate reads the date formats feeds use in practice: RFC 822 variants
+// for RSS, RFC 3339 for Atom and JSON Feed. It is zero when s is none of them.
+func ParseDate(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{
+		time.RFC1123Z, time.RFC1123, time.RFC3339Nano,
+		"Mon, 2 Jan 2006 15:04:05 -0700", "Mon, 2 Jan 2006 15:04:05 MST",
+		time.RFC822Z, time.RFC822, "2 Jan 2006 15:04:05 -0700",
+		"2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02",
+	} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
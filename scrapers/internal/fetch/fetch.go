@@ -0,0 +1,224 @@
+package fetch
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+	"github.com/groot34/job-aggregator/scraper/internal/fixtures"
+	"github.com/groot34/job-aggregator/scraper/internal/httpcache"
+)
+
+// Limit throttles requests to domains matching DomainGlob
+type Limit struct {
+	DomainGlob  string
+	Delay       time.Duration
+	RandomDelay time.Duration
+	Parallelism int
+}
+
+// DefaultLimits are applied in order; colly uses the first rule whose glob matches.
+// Per-site rules are added by RegisterLimit when parsers register themselves.
+var DefaultLimits = []Limit{
+	{DomainGlob: "*", Delay: time.Second, RandomDelay: time.Second, Parallelism: 2},
+}
+
+// RegisterLimit adds a per-domain default ahead of the catch-all rule.
+// It is meant to be called during start-up, before any collector is built.
+func RegisterLimit(l Limit) {
+	n := len(DefaultLimits)
+	if n > 0 && DefaultLimits[n-1].DomainGlob == "*" {
+		DefaultLimits = append(DefaultLimits[:n-1:n-1], l, DefaultLimits[n-1])
+		return
+	}
+	DefaultLimits = append(DefaultLimits, l)
+}
+
+// DefaultUserAgents are rotated through when FETCH_USER_AGENTS is not set
+var DefaultUserAgents = []string{
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15",
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0",
+}
+
+// DefaultHeaders are sent with every request unless a parser overrides them
+var DefaultHeaders = map[string]string{
+	"Accept":                    "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,*/*;q=0.8",
+	"Accept-Language":           "en-US,en;q=0.9",
+	"Upgrade-Insecure-Requests": "1",
+}
+
+// Config controls how collectors are built
+type Config struct {
+	UserAgents   []string
+	Headers      map[string]string
+	Limits       []Limit
+	IgnoreRobots bool
+	Timeout      time.Duration
+	Cache        *httpcache.Cache // nil disables response caching
+}
+
+// ConfigFromEnv starts from the defaults and applies FETCH_USER_AGENTS
+// (separated by "|"), FETCH_IGNORE_ROBOTS and FETCH_TIMEOUT
+func ConfigFromEnv() Config {
+	cfg := Config{
+		UserAgents: DefaultUserAgents,
+		Headers:    DefaultHeaders,
+		Limits:     DefaultLimits,
+		Timeout:    30 * time.Second,
+		Cache:      httpcache.Default(),
+	}
+	if v := os.Getenv("FETCH_USER_AGENTS"); v != "" {
+		var agents []string
+		for _, ua := range strings.Split(v, "|") {
+			if ua = strings.TrimSpace(ua); ua != "" {
+				agents = append(agents, ua)
+			}
+		}
+		if len(agents) > 0 {
+			cfg.UserAgents = agents
+		}
+	}
+	if v, err := strconv.ParseBool(os.Getenv("FETCH_IGNORE_ROBOTS")); err == nil {
+		cfg.IgnoreRobots = v
+	}
+	if d, err := time.ParseDuration(os.Getenv("FETCH_TIMEOUT")); err == nil {
+		cfg.Timeout = d
+	}
+	return cfg
+}
+
+// Option customises a single collector on top of the shared config
+type Option func(*options)
+
+type options struct {
+	headers map[string]string
+}
+
+// WithHeaders adds or overrides request headers for this collector only
+func WithHeaders(h map[string]string) Option {
+	return func(o *options) {
+		for k, v := range h {
+			o.headers[k] = v
+		}
+	}
+}
+
+// uaCounter is shared by all collectors so consecutive requests rotate agents
+var uaCounter uint64
+
+// NewCollector builds a colly collector for source using ConfigFromEnv
+func NewCollector(source string, opts ...Option) *colly.Collector {
+	return ConfigFromEnv().NewCollector(source, opts...)
+}
+
+// NewCollector builds a collector with rate limits, robots.txt handling,
+// rotating user agents, default headers and request logging attached
+func (cfg Config) NewCollector(source string, opts ...Option) *colly.Collector {
+	o := options{headers: make(map[string]string, len(cfg.Headers))}
+	for k, v := range cfg.Headers {
+		o.headers[k] = v
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	c := colly.NewCollector()
+	c.IgnoreRobotsTxt = cfg.IgnoreRobots
+	if cfg.Timeout > 0 {
+		c.SetRequestTimeout(cfg.Timeout)
+	}
+
+	// Transport chain: cache -> fixture recorder -> replay rewrite -> network
+	var transport http.RoundTripper = http.DefaultTransport
+	limits := cfg.Limits
+	if replay := fixtures.ReplayURL(); replay != "" {
+		transport = fixtures.RewriteTransport(replay, transport)
+		limits = nil // fixtures are served locally, there is no one to be polite to
+	}
+	if rec := fixtures.ActiveRecorder(); rec != nil {
+		transport = rec.Transport(transport)
+	}
+	if cfg.Cache != nil {
+		transport = cfg.Cache.Transport(transport)
+	}
+	c.WithTransport(transport)
+
+	for _, l := range limits {
+		if err := c.Limit(&colly.LimitRule{
+			DomainGlob:  l.DomainGlob,
+			Delay:       l.Delay,
+			RandomDelay: l.RandomDelay,
+			Parallelism: l.Parallelism,
+		}); err != nil {
+			fmt.Printf("⚠️  [%s] Ignoring invalid rate limit for %s: %v\n", source, l.DomainGlob, err)
+		}
+	}
+
+	c.OnRequest(func(r *colly.Request) {
+		if len(cfg.UserAgents) > 0 {
+			n := atomic.AddUint64(&uaCounter, 1)
+			r.Headers.Set("User-Agent", cfg.UserAgents[int(n-1)%len(cfg.UserAgents)])
+		}
+		for k, v := range o.headers {
+			r.Headers.Set(k, v)
+		}
+		fmt.Printf("🌐 [%s] Visiting %s\n", source, r.URL)
+	})
+	c.OnResponse(func(r *colly.Response) {
+		fmt.Printf("📥 [%s] %d %s (%d bytes)\n", source, r.StatusCode, r.Request.URL, len(r.Body))
+	})
+	c.OnError(func(r *colly.Response, err error) {
+		fmt.Printf("❌ [%s] %s failed: %v\n", source, r.Request.URL, err)
+	})
+
+	return c
+}
+
+// Getter fetches one page at a time and hands back its body. Bodies are filed
+// under the URL the caller asked for: colly rewrites the request URL (a bare
+// host gains a "/") and the response's may be a redirect or a replay server.
+// A Getter may be used from several goroutines at once.
+type Getter struct {
+	c      *colly.Collector
+	mu     sync.Mutex
+	bodies map[string][]byte
+}
+
+// NewGetter builds a Getter around a collector for source
+func NewGetter(source string, opts ...Option) *Getter {
+	g := &Getter{c: NewCollector(source, opts...), bodies: make(map[string][]byte)}
+	g.c.OnResponse(func(r *colly.Response) {
+		if u := r.Ctx.Get("getter.url"); u != "" {
+			g.mu.Lock()
+			g.bodies[u] = r.Body
+			g.mu.Unlock()
+		}
+	})
+	return g
+}
+
+// Get fetches rawURL synchronously and returns its body
+func (g *Getter) Get(rawURL string) ([]byte, error) {
+	ctx := colly.NewContext()
+	ctx.Put("getter.url", rawURL)
+	if err := g.c.Request("GET", rawURL, nil, ctx, nil); err != nil {
+		return nil, err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	body, ok := g.bodies[rawURL]
+	if !ok {
+		return nil, fmt.Errorf("no response from %s", rawURL)
+	}
+	delete(g.bodies, rawURL)
+	return body, nil
+}
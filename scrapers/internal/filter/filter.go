@@ -0,0 +1,427 @@
+package filter
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/company"
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+)
+
+// Rule matches a job's company by one of exact name, regular expression or domain
+type Rule struct {
+	Exact  string `json:"exact,omitempty"`  // company name, compared after normalization ("Acme Inc." matches "Acme")
+	Regex  string `json:"regex,omitempty"`  // matched against the raw company name
+	Domain string `json:"domain,omitempty"` // matches the job URL's or the company's domain, and their subdomains
+	Reason string `json:"reason,omitempty"` // shown in the report instead of the rule itself
+
+	re *regexp.Regexp
+}
+
+// Config is the filter configuration. Allow rules win over everything else,
+// so a known good company is never dropped by a heuristic.
+type Config struct {
+	Block []Rule `json:"block"`
+	Allow []Rule `json:"allow"`
+
+	Staffing     bool `json:"staffing"`     // drop jobs that look like staffing-agency listings
+	Confidential bool `json:"confidential"` // drop jobs whose company is withheld ("Confidential", "Undisclosed")
+	Reposts      bool `json:"reposts"`      // keep only one of several postings with the same company, title, location and description
+
+	// TemplateCompanies drops jobs whose description is reused word for word
+	// by at least this many different companies; 0 disables the check
+	TemplateCompanies int `json:"templateCompanies"`
+}
+
+// DefaultConfig enables every heuristic and blocks nothing by name
+func DefaultConfig() Config {
+	return Config{Staffing: true, Confidential: true, Reposts: true, TemplateCompanies: 3}
+}
+
+// Path returns FILTERS_PATH, defaulting to filters.json
+func Path() string {
+	if p := os.Getenv("FILTERS_PATH"); p != "" {
+		return p
+	}
+	return "filters.json"
+}
+
+// Load reads a config from path on top of DefaultConfig. A missing file yields the defaults.
+func Load(path string) (Config, error) {
+	cfg := DefaultConfig()
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read filters: %v", err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to decode filters: %v", err)
+	}
+	for _, rules := range [][]Rule{cfg.Block, cfg.Allow} {
+		for i := range rules {
+			if err := rules[i].compile(); err != nil {
+				return cfg, fmt.Errorf("%s: %v", path, err)
+			}
+		}
+	}
+	return cfg, nil
+}
+
+func (r *Rule) compile() error {
+	n := 0
+	for _, v := range []string{r.Exact, r.Regex, r.Domain} {
+		if v != "" {
+			n++
+		}
+	}
+	if n != 1 {
+		return fmt.Errorf("rule %+v must set exactly one of exact, regex or domain", *r)
+	}
+	if r.Regex != "" {
+		re, err := regexp.Compile(r.Regex)
+		if err != nil {
+			return fmt.Errorf("invalid regex %q: %v", r.Regex, err)
+		}
+		r.re = re
+	}
+	r.Domain = company.Domain(r.Domain)
+	return nil
+}
+
+// String describes the rule for reports
+func (r Rule) String() string {
+	switch {
+	case r.Reason != "":
+		return r.Reason
+	case r.Exact != "":
+		return "company " + r.Exact
+	case r.Regex != "":
+		return "company matches " + r.Regex
+	default:
+		return "domain " + r.Domain
+	}
+}
+
+func (r Rule) match(j models.Job, domains []string) bool {
+	switch {
+	case r.Exact != "":
+		key := company.Normalize(r.Exact).Key
+		return key != "" && key == company.Normalize(j.Company).Key
+	case r.re != nil:
+		return r.re.MatchString(j.Company)
+	case r.Domain != "":
+		for _, d := range domains {
+			if d == r.Domain || strings.HasSuffix(d, "."+r.Domain) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Reason says which check dropped a job
+type Reason string
+
+const (
+	ReasonBlocked      Reason = "blocked"
+	ReasonStaffing     Reason = "staffing"
+	ReasonConfidential Reason = "confidential"
+	ReasonRepost       Reason = "repost"
+	ReasonTemplate     Reason = "template"
+)
+
+// Dropped is one filtered-out job and why
+type Dropped struct {
+	JobID   string `json:"externalId"`
+	Title   string `json:"title"`
+	Company string `json:"company"`
+	Source  string `json:"source"`
+	Reason  Reason `json:"reason"`
+	Detail  string `json:"detail"` // the matching rule, phrase or original posting
+}
+
+// Report lists what one run filtered out
+type Report struct {
+	At      time.Time `json:"at"`
+	Checked int       `json:"checked"`
+	Kept    int       `json:"kept"`
+	Allowed int       `json:"allowed"` // matched an allow rule, so skipped every other check
+	Dropped []Dropped `json:"dropped"`
+
+	// Reposts maps each repost group to the posting kept for it, so the next
+	// run can keep the same one
+	Reposts map[string]string `json:"reposts,omitempty"`
+}
+
+// LoadReport reads a report written by Save. A missing file yields an empty report.
+func LoadReport(path string) (Report, error) {
+	var r Report
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return r, nil
+	}
+	if err != nil {
+		return r, fmt.Errorf("failed to read filter report: %v", err)
+	}
+	if err := json.Unmarshal(data, &r); err != nil {
+		return r, fmt.Errorf("failed to decode filter report: %v", err)
+	}
+	return r, nil
+}
+
+// Counts returns the number of dropped jobs per reason
+func (r Report) Counts() map[Reason]int {
+	counts := make(map[Reason]int)
+	for _, d := range r.Dropped {
+		counts[d.Reason]++
+	}
+	return counts
+}
+
+// Save writes the report as JSON
+func (r Report) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode filter report: %v", err)
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create filter report dir: %v", err)
+		}
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write filter report: %v", err)
+	}
+	return nil
+}
+
+// Filter drops unwanted jobs according to a Config
+type Filter struct {
+	cfg Config
+
+	// CompanyDomain optionally returns the company's own domain for domain
+	// rules, e.g. from the company directory; the job URL's domain is always checked
+	CompanyDomain func(models.Job) string
+}
+
+// New returns a filter for cfg, validating rules built in code rather than loaded
+func New(cfg Config) (*Filter, error) {
+	for _, rules := range [][]Rule{cfg.Block, cfg.Allow} {
+		for i := range rules {
+			if err := rules[i].compile(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &Filter{cfg: cfg}, nil
+}
+
+// Run filters one scrape's jobs one at a time, as they stream in. It can't
+// look ahead: a template description is only dropped once TemplateCompanies
+// companies have used it, so the first copies get through, and a new repost
+// group keeps the first posting to arrive.
+//
+// A group that had a survivor last run keeps it so the survivor stays put
+// however the sources are ordered. Postings of the group that arrive before
+// it are held back; if it never arrives (it was delisted or is now filtered
+// out), Finish releases the earliest posted of them instead.
+type Run struct {
+	f         *Filter
+	report    Report
+	templates map[string]map[string]bool // description hash -> companies using it so far
+	previous  map[string]string          // repost key -> posting kept last run
+	held      map[string][]models.Job    // repost key -> postings waiting for last run's survivor
+}
+
+// Start begins a streaming run; previous is the last run's Report.Reposts, or nil
+func (f *Filter) Start(now time.Time, previous map[string]string) *Run {
+	return &Run{
+		f:         f,
+		report:    Report{At: now, Reposts: make(map[string]string)},
+		templates: make(map[string]map[string]bool),
+		previous:  previous,
+		held:      make(map[string][]models.Job),
+	}
+}
+
+// Check reports whether to pass j on now. A job it neither passes on nor
+// records as dropped is held back until last run's survivor of its repost
+// group shows up, or until Finish.
+func (r *Run) Check(j models.Job) bool {
+	r.report.Checked++
+	allowed, reason, detail := r.f.check(j)
+	if allowed {
+		r.report.Allowed++
+		r.report.Kept++
+		return true
+	}
+	if reason != "" {
+		return r.drop(j, reason, detail)
+	}
+	if r.f.cfg.TemplateCompanies > 0 {
+		if h := descriptionHash(j.Description); h != "" {
+			if r.templates[h] == nil {
+				r.templates[h] = make(map[string]bool)
+			}
+			r.templates[h][company.Normalize(j.Company).Key] = true
+			if n := len(r.templates[h]); n >= r.f.cfg.TemplateCompanies {
+				return r.drop(j, ReasonTemplate, fmt.Sprintf("description shared by %d companies", n))
+			}
+		}
+	}
+	if key := repostKey(j); r.f.cfg.Reposts && key != "" {
+		if kept, ok := r.report.Reposts[key]; ok {
+			if kept != j.ID {
+				return r.drop(j, ReasonRepost, "repost of "+kept)
+			}
+		} else if last, ok := r.previous[key]; ok && last != j.ID {
+			r.held[key] = append(r.held[key], j)
+			return false
+		} else {
+			// Last run's survivor, or the first of a new group: whatever was held is a repost of it
+			for _, h := range r.held[key] {
+				r.drop(h, ReasonRepost, "repost of "+j.ID)
+			}
+			delete(r.held, key)
+			r.report.Reposts[key] = j.ID
+		}
+	}
+	r.report.Kept++
+	return true
+}
+
+// Finish ends the run and returns the held-back jobs to pass on after all: in
+// each group whose survivor from last run didn't show up, the earliest posted
+func (r *Run) Finish() []models.Job {
+	keys := make([]string, 0, len(r.held))
+	for key := range r.held {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var kept []models.Job
+	for _, key := range keys {
+		group := r.held[key]
+		first := group[0]
+		for _, j := range group[1:] {
+			if postedBefore(j, first) {
+				first = j
+			}
+		}
+		for _, j := range group {
+			if j.ID != first.ID {
+				r.drop(j, ReasonRepost, "repost of "+first.ID)
+			}
+		}
+		r.report.Reposts[key] = first.ID
+		r.report.Kept++
+		kept = append(kept, first)
+	}
+	r.held = make(map[string][]models.Job)
+	return kept
+}
+
+// drop records j as dropped for reason; it returns false for Check to pass back
+func (r *Run) drop(j models.Job, reason Reason, detail string) bool {
+	r.report.Dropped = append(r.report.Dropped, Dropped{
+		JobID: j.ID, Title: j.Title, Company: j.Company, Source: j.Source, Reason: reason, Detail: detail,
+	})
+	return false
+}
+
+// Report returns what the run has dropped so far
+func (r *Run) Report() Report {
+	report := r.report
+	report.Dropped = append([]Dropped(nil), r.report.Dropped...)
+	sort.SliceStable(report.Dropped, func(a, b int) bool { return report.Dropped[a].Reason < report.Dropped[b].Reason })
+	return report
+}
+
+// check runs the checks that look at one job alone. allowed means an allow
+// rule matched, which skips every other check; a reason means the job is dropped.
+func (f *Filter) check(j models.Job) (allowed bool, reason Reason, detail string) {
+	domains := f.domains(j)
+	if _, ok := firstMatch(f.cfg.Allow, j, domains); ok {
+		return true, "", ""
+	}
+	if rule, ok := firstMatch(f.cfg.Block, j, domains); ok {
+		return false, ReasonBlocked, rule.String()
+	}
+	if f.cfg.Confidential && confidential(j.Company) {
+		return false, ReasonConfidential, j.Company
+	}
+	if f.cfg.Staffing {
+		if detail, ok := staffing(j); ok {
+			return false, ReasonStaffing, detail
+		}
+	}
+	return false, "", ""
+}
+
+// repostKey groups postings of the same role: same company, title, location and
+// description. Without a company, the same title from two jobs says nothing.
+func repostKey(j models.Job) string {
+	companyKey := company.Normalize(j.Company).Key
+	if companyKey == "" {
+		return ""
+	}
+	parts := []string{companyKey, j.Title, j.Location, j.Description}
+	for i, p := range parts {
+		parts[i] = strings.ToLower(strings.Join(strings.Fields(p), " "))
+	}
+	sum := sha1.Sum([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// postedBefore orders the postings of one role: earliest posting day first, then lowest ID.
+// Days rather than instants, since sources without dates stamp jobs with the scrape time.
+func postedBefore(a, b models.Job) bool {
+	da, db := a.PostedAt.UTC().Truncate(24*time.Hour), b.PostedAt.UTC().Truncate(24*time.Hour)
+	if !da.Equal(db) {
+		return da.Before(db)
+	}
+	return a.ID < b.ID
+}
+
+func (f *Filter) domains(j models.Job) []string {
+	var domains []string
+	if d := company.Domain(j.URL); d != "" {
+		domains = append(domains, d)
+	}
+	if f.CompanyDomain != nil {
+		if d := company.Domain(f.CompanyDomain(j)); d != "" {
+			domains = append(domains, d)
+		}
+	}
+	return domains
+}
+
+func firstMatch(rules []Rule, j models.Job, domains []string) (Rule, bool) {
+	for _, r := range rules {
+		if r.match(j, domains) {
+			return r, true
+		}
+	}
+	return Rule{}, false
+}
+
+// descriptionHash fingerprints descriptions long enough that sharing one
+// can't be a coincidence; short ones hash to ""
+func descriptionHash(desc string) string {
+	norm := strings.ToLower(strings.Join(strings.Fields(desc), " "))
+	if len(norm) < 200 {
+		return ""
+	}
+	sum := sha1.Sum([]byte(norm))
+	return hex.EncodeToString(sum[:])
+}
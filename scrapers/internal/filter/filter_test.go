@@ -0,0 +1,157 @@
+package filter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+)
+
+func ids(jobs []models.Job) []string {
+	var out []string
+	for _, j := range jobs {
+		out = append(out, j.ID)
+	}
+	return out
+}
+
+func TestStaffing(t *testing.T) {
+	for _, tc := range []struct {
+		company, description string
+		want                 bool
+	}{
+		{"ABC Staffing Pvt Ltd", "Backend role", true},
+		{"Prime Recruiters", "Backend role", true},
+		{"Acme", "Our client, a leading fintech in Bangalore, is looking for a Go developer.", true},
+		{"Acme", "We are hiring for one of our esteemed clients, an MNC.", true},
+		{"Acme", "We are a recruitment agency placing engineers across Europe.", true},
+		{"Acme", "The role is on third-party payroll for 12 months.", true},
+
+		// Ordinary employers talk about their clients too
+		{"Stripe", "You'll build tools our clients use to run their payments.", false},
+		{"Globex", "Work on behalf of a customer to migrate their data platform.", false},
+		{"Initech", "Join the payroll of a growing team and help our clients succeed.", false},
+		{"Hooli", "Partner with our client success team on onboarding.", false},
+	} {
+		_, got := staffing(models.Job{Company: tc.company, Description: tc.description})
+		if got != tc.want {
+			t.Errorf("staffing(%q, %q) = %v, want %v", tc.company, tc.description, got, tc.want)
+		}
+	}
+}
+
+func TestConfidential(t *testing.T) {
+	for name, want := range map[string]bool{
+		"Confidential":        true,
+		"Undisclosed Company": true,
+		"A Leading MNC":       true,
+		"Confidential Labs":   false,
+		"Acme":                false,
+	} {
+		if got := confidential(name); got != want {
+			t.Errorf("confidential(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+// check passes jobs through run as the filter stage does, Finish included
+func check(run *Run, jobs ...models.Job) []string {
+	var kept []string
+	for _, j := range jobs {
+		if run.Check(j) {
+			kept = append(kept, j.ID)
+		}
+	}
+	return append(kept, ids(run.Finish())...)
+}
+
+func TestRunChecks(t *testing.T) {
+	f, err := New(Config{
+		Block:             []Rule{{Exact: "Spam Corp"}, {Domain: "spamjobs.example.com"}},
+		Allow:             []Rule{{Exact: "Talent Solutions Inc"}},
+		Staffing:          true,
+		Confidential:      true,
+		TemplateCompanies: 3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := strings.Repeat("Exciting opportunity with great growth and benefits. ", 5)
+	jobs := []models.Job{
+		{ID: "ok", Company: "Acme", Title: "Go Engineer"},
+		{ID: "blocked", Company: "Spam Corp Ltd", Title: "Go Engineer"},
+		{ID: "domain", Company: "Whoever", URL: "https://jobs.spamjobs.example.com/1"},
+		{ID: "allowed", Company: "Talent Solutions Inc", Title: "Go Engineer"},
+		{ID: "agency", Company: "Fast Staffing", Title: "Go Engineer"},
+		{ID: "hidden", Company: "Confidential", Title: "Go Engineer"},
+		// Templates are dropped from the copy that reaches the threshold on
+		{ID: "t1", Company: "One", Description: template},
+		{ID: "t2", Company: "Two", Description: template},
+		{ID: "t3", Company: "Three", Description: template},
+	}
+
+	run := f.Start(time.Now(), nil)
+	if got, want := check(run, jobs...), []string{"ok", "allowed", "t1", "t2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("kept %v, want %v", got, want)
+	}
+	report := run.Report()
+	want := map[Reason]int{ReasonBlocked: 2, ReasonStaffing: 1, ReasonConfidential: 1, ReasonTemplate: 1}
+	if got := report.Counts(); !reflect.DeepEqual(got, want) {
+		t.Errorf("counts = %v, want %v", got, want)
+	}
+	if report.Allowed != 1 || report.Kept != 4 || report.Checked != len(jobs) {
+		t.Errorf("report = %+v", report)
+	}
+}
+
+func TestRunKeepsLastSurvivor(t *testing.T) {
+	f, err := New(Config{Reposts: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := func(n int) time.Time { return time.Date(2024, 3, n, 12, 0, 0, 0, time.UTC) }
+	role := func(id string, posted time.Time) models.Job {
+		return models.Job{ID: id, Company: "Acme Inc.", Title: "Go  Engineer", Location: "Berlin", Description: "Build APIs", PostedAt: posted}
+	}
+
+	// First run: the first to arrive is kept; another city or description is another role
+	first := f.Start(day(4), nil)
+	got := check(first, role("lv-1", day(3)), role("gh-1", day(1)),
+		models.Job{ID: "li-3", Company: "Acme", Title: "Go Engineer", Location: "Munich", Description: "Build APIs"},
+		models.Job{ID: "li-4", Company: "Acme", Title: "Go Engineer", Location: "Berlin", Description: "Platform team"})
+	if want := []string{"lv-1", "li-3", "li-4"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("first run kept %v, want %v", got, want)
+	}
+
+	// Next run the sources finish in the other order; lv-1 still wins and gh-1,
+	// held back until it came, is dropped as its repost
+	second := f.Start(day(5), first.Report().Reposts)
+	if got := check(second, role("gh-1", day(1)), role("lv-1", day(3))); !reflect.DeepEqual(got, []string{"lv-1"}) {
+		t.Errorf("second run kept %v, want lv-1 again", got)
+	}
+	for _, d := range second.Report().Dropped {
+		if d.JobID != "gh-1" || d.Reason != ReasonRepost || d.Detail != "repost of lv-1" {
+			t.Errorf("dropped %+v, want gh-1 as a repost of lv-1", d)
+		}
+	}
+
+	// lv-1 is delisted: rather than losing the role, the earliest posted of the rest is kept
+	third := f.Start(day(6), second.Report().Reposts)
+	if got := check(third, role("li-2", day(2)), role("gh-1", day(1))); !reflect.DeepEqual(got, []string{"gh-1"}) {
+		t.Errorf("third run kept %v, want gh-1 once lv-1 never came", got)
+	}
+	if kept := third.Report().Reposts; len(kept) != 1 || !containsValue(kept, "gh-1") {
+		t.Errorf("third run survivors = %v, want gh-1", kept)
+	}
+}
+
+func containsValue(m map[string]string, v string) bool {
+	for _, x := range m {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package filter
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+)
+
+// Company names that say the employer is being withheld
+var withheld = regexp.MustCompile(`(?i)^\s*(company\s+)?(confidential|undisclosed|not\s+disclosed|hidden|anonymous)(\s+(company|client|employer))?\s*$|^\s*(a\s+)?(leading|reputed|top)\s+(mnc|company|client|organi[sz]ation)\s*$`)
+
+// Words in a company name that mark a staffing or recruitment agency
+var staffingName = regexp.MustCompile(`(?i)\b(staffing|recruit(ment|ers|ing)?|manpower|placements?|consultancy|consultants|headhunt(ers|ing)?|talent\s+(acquisition|solutions|partners)|hr\s+(solutions|services|consultants?)|workforce\s+solutions|outsourcing|executive\s+search|search\s+partners)\b`)
+
+// Wording only agencies use about the employer they're hiring for. Generic
+// phrases such as "our clients" are left out: plenty of employers have clients.
+var staffingPhrases = regexp.MustCompile(`(?i)\b(our\s+client,?\s+(is\s+)?an?\s+(leading|well[\s-]known|reputed|renowned|reputable|fast[\s-]growing|prestigious|top|global|large)|(hiring|recruiting)\s+(for|on\s+behalf\s+of)\s+(one\s+of\s+)?our\s+(esteemed\s+)?clients?|client\s+of\s+ours|we\s+are\s+an?\s+(staffing|recruitment|recruiting)\s+(agency|firm|company)|third[\s-]party\s+payroll)\b`)
+
+// confidential reports whether the company name withholds the employer
+func confidential(name string) bool {
+	return withheld.MatchString(name)
+}
+
+// staffing reports whether a job looks like an agency listing, with the evidence
+func staffing(j models.Job) (string, bool) {
+	if m := staffingName.FindString(j.Company); m != "" {
+		return "company name: " + strings.ToLower(m), true
+	}
+	if m := staffingPhrases.FindString(j.Description); m != "" {
+		return "description: " + strings.ToLower(strings.Join(strings.Fields(m), " ")), true
+	}
+	return "", false
+}
@@ -0,0 +1,261 @@
+package fixtures
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/groot34/job-aggregator/scraper/internal/httpcache"
+)
+
+const manifestFile = "manifest.json"
+
+// Entry describes one recorded page
+type Entry struct {
+	Method      string `json:"method"`
+	URL         string `json:"url"`
+	File        string `json:"file"`
+	StatusCode  int    `json:"statusCode"`
+	ContentType string `json:"contentType,omitempty"`
+}
+
+// key matches requests on path and query only, so fixtures replay on any host
+func (e Entry) key() string {
+	u, err := url.Parse(e.URL)
+	if err != nil {
+		return e.URL
+	}
+	return u.RequestURI()
+}
+
+// Recorder saves every visited page into a fixtures directory
+type Recorder struct {
+	dir     string
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// NewRecorder records into dir, keeping any entries already recorded there
+func NewRecorder(dir string) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create fixtures dir: %v", err)
+	}
+	r := &Recorder{dir: dir}
+	if entries, err := readManifest(dir); err == nil {
+		r.entries = entries
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Record stores body as the fixture for method+rawURL, replacing any previous recording
+func (r *Recorder) Record(method, rawURL, contentType string, status int, body []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	idx := -1
+	for i, e := range r.entries {
+		if e.Method == method && e.URL == rawURL {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		r.entries = append(r.entries, Entry{
+			Method: method,
+			URL:    rawURL,
+			File:   fmt.Sprintf("%03d.html", len(r.entries)+1),
+		})
+		idx = len(r.entries) - 1
+	}
+	r.entries[idx].StatusCode = status
+	r.entries[idx].ContentType = contentType
+
+	if err := os.WriteFile(filepath.Join(r.dir, r.entries[idx].File), body, 0o644); err != nil {
+		return fmt.Errorf("failed to write fixture: %v", err)
+	}
+	data, err := json.MarshalIndent(r.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode fixture manifest: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(r.dir, manifestFile), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write fixture manifest: %v", err)
+	}
+	return nil
+}
+
+// Transport records every GET response that passes through base
+func (r *Recorder) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := base.RoundTrip(req)
+		if err != nil || req.Method != http.MethodGet || req.URL.Path == "/robots.txt" {
+			return resp, err
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+
+		if err := r.Record(req.Method, req.URL.String(), resp.Header.Get("Content-Type"), resp.StatusCode, body); err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+		} else {
+			fmt.Printf("📼 Recorded %s\n", req.URL)
+		}
+		return resp, nil
+	})
+}
+
+// Set is a loaded fixtures directory that can be served for replay
+type Set struct {
+	dir     string
+	entries map[string]Entry // request URI -> entry; HTTP recordings win over renders
+}
+
+// Load reads the manifest in dir
+func Load(dir string) (*Set, error) {
+	entries, err := readManifest(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fixtures from %s: %v", dir, err)
+	}
+
+	s := &Set{dir: dir, entries: make(map[string]Entry)}
+	for _, e := range entries {
+		if prev, ok := s.entries[e.key()]; ok && prev.Method != httpcache.MethodRender {
+			continue
+		}
+		s.entries[e.key()] = e
+	}
+	return s, nil
+}
+
+// ServeHTTP answers with the recorded page for the request's path and query
+func (s *Set) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e, ok := s.entries[r.URL.RequestURI()]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	body, err := os.ReadFile(filepath.Join(s.dir, e.File))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	contentType := e.ContentType
+	if contentType == "" {
+		contentType = "text/html; charset=utf-8"
+	}
+	w.Header().Set("Content-Type", contentType)
+	status := e.StatusCode
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// Start serves the fixtures from a local httptest server; the caller must Close it
+func (s *Set) Start() *httptest.Server {
+	return httptest.NewServer(s)
+}
+
+// Rewrite points rawURL at the replay server, keeping its path and query
+func Rewrite(serverURL, rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	base, err := url.Parse(serverURL)
+	if err != nil {
+		return rawURL
+	}
+	u.Scheme, u.Host = base.Scheme, base.Host
+	return u.String()
+}
+
+// RewriteTransport sends every request to the replay server instead of its real host
+func RewriteTransport(serverURL string, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	target, _ := url.Parse(serverURL)
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		req = req.Clone(req.Context())
+		req.URL.Scheme, req.URL.Host = target.Scheme, target.Host
+		req.Host = target.Host
+		return base.RoundTrip(req)
+	})
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func readManifest(dir string) ([]Entry, error) {
+	data, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode fixture manifest: %v", err)
+	}
+	return entries, nil
+}
+
+var (
+	defaultMu sync.RWMutex
+	recorder  *Recorder
+	replayURL string
+)
+
+// SetRecorder makes fetchers and headless parsers record into r (nil stops recording)
+func SetRecorder(r *Recorder) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	recorder = r
+}
+
+// ActiveRecorder returns the recorder installed with SetRecorder, if any
+func ActiveRecorder() *Recorder {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return recorder
+}
+
+// SetReplay routes all fetches to the replay server at serverURL ("" turns replay off)
+func SetReplay(serverURL string) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	replayURL = serverURL
+}
+
+// ReplayURL returns the active replay server URL, or "" when replay is off
+func ReplayURL() string {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return replayURL
+}
+
+// URL rewrites rawURL to the replay server when replay is active.
+// Headless browsers use this since they don't go through an http.RoundTripper.
+func URL(rawURL string) string {
+	if s := ReplayURL(); s != "" {
+		return Rewrite(s, rawURL)
+	}
+	return rawURL
+}
@@ -0,0 +1,115 @@
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// baselineWindow is how many previous runs per source the baseline keeps
+const baselineWindow = 10
+
+// acceptAfter is how many anomalous runs in a row make their level the new normal
+const acceptAfter = 3
+
+// Run is one recorded scrape in the baseline
+type Run struct {
+	At time.Time `json:"at"`
+	Metrics
+}
+
+// Baseline is the rolling history of recent runs, keyed by source. Anomalous
+// runs are held apart so one broken scrape can't drag the baseline down.
+type Baseline struct {
+	path string
+	Runs map[string][]Run `json:"runs"`
+	Held map[string][]Run `json:"held,omitempty"` // anomalous runs in a row, not yet part of Runs
+}
+
+// NewBaseline returns an empty baseline that will be saved to path
+func NewBaseline(path string) *Baseline {
+	return &Baseline{path: path, Runs: make(map[string][]Run), Held: make(map[string][]Run)}
+}
+
+// LoadBaseline reads the baseline from path; a missing file yields an empty baseline
+func LoadBaseline(path string) (*Baseline, error) {
+	b := NewBaseline(path)
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return b, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read health baseline: %v", err)
+	}
+	if err := json.Unmarshal(data, b); err != nil {
+		return nil, fmt.Errorf("failed to decode health baseline: %v", err)
+	}
+	if b.Runs == nil {
+		b.Runs = make(map[string][]Run)
+	}
+	if b.Held == nil {
+		b.Held = make(map[string][]Run)
+	}
+	return b, nil
+}
+
+// Metrics returns the recorded metrics for source, oldest first
+func (b *Baseline) Metrics(source string) []Metrics {
+	runs := b.Runs[source]
+	out := make([]Metrics, len(runs))
+	for i, r := range runs {
+		out[i] = r.Metrics
+	}
+	return out
+}
+
+// Record appends a healthy run for source, dropping the oldest beyond the
+// window. It ends any streak of anomalous runs.
+func (b *Baseline) Record(source string, m Metrics, at time.Time) {
+	runs := append(b.Runs[source], Run{At: at, Metrics: m})
+	if len(runs) > baselineWindow {
+		runs = runs[len(runs)-baselineWindow:]
+	}
+	b.Runs[source] = runs
+	delete(b.Held, source)
+}
+
+// Hold sets an anomalous run for source aside. Once acceptAfter have come in
+// a row the change has lasted, so they replace the source's history and Hold
+// reports true.
+func (b *Baseline) Hold(source string, m Metrics, at time.Time) bool {
+	held := append(b.Held[source], Run{At: at, Metrics: m})
+	if len(held) < acceptAfter {
+		b.Held[source] = held
+		return false
+	}
+	b.Runs[source] = held
+	delete(b.Held, source)
+	return true
+}
+
+// Reset makes one run the whole of source's history, for a change accepted by hand
+func (b *Baseline) Reset(source string, m Metrics, at time.Time) {
+	b.Runs[source] = []Run{{At: at, Metrics: m}}
+	delete(b.Held, source)
+}
+
+// Save writes the baseline back to the path it was loaded from
+func (b *Baseline) Save() error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode health baseline: %v", err)
+	}
+	if dir := filepath.Dir(b.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create health dir: %v", err)
+		}
+	}
+	if err := os.WriteFile(b.path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write health baseline: %v", err)
+	}
+	return nil
+}
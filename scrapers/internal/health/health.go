@@ -0,0 +1,160 @@
+package health
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+)
+
+// Fields whose fill-rate is tracked for every source
+var Fields = []string{"title", "company", "location", "description", "url", "salary"}
+
+// placeholders are values parsers use when a selector found nothing
+var placeholders = map[string]bool{
+	"unknown":           true,
+	"view on wellfound": true,
+	"click to apply on linkedin to view full description.": true,
+}
+
+// Expectations are the structural signals a parser promises on a healthy page
+type Expectations struct {
+	MinJobs   int
+	FillRates map[string]float64 // field -> minimum fraction of jobs with it filled
+}
+
+// Metrics describe one scrape of one source
+type Metrics struct {
+	Jobs      int                `json:"jobs"`
+	FillRates map[string]float64 `json:"fillRates"`
+}
+
+// Measure computes the job count and per-field fill-rates
+func Measure(jobs []models.Job) Metrics {
+	var m Meter
+	for _, j := range jobs {
+		m.Add(j)
+	}
+	return m.Metrics()
+}
+
+// Meter computes Metrics one job at a time, for scrapes that are streamed
+// rather than collected. The zero value is ready to use.
+type Meter struct {
+	jobs   int
+	filled map[string]int
+}
+
+// Add counts one job
+func (m *Meter) Add(j models.Job) {
+	if m.filled == nil {
+		m.filled = make(map[string]int, len(Fields))
+	}
+	m.jobs++
+	for field, value := range map[string]string{
+		"title":       j.Title,
+		"company":     j.Company,
+		"location":    j.Location,
+		"description": j.Description,
+		"url":         j.URL,
+		"salary":      j.Salary,
+	} {
+		v := strings.TrimSpace(value)
+		if v != "" && !placeholders[strings.ToLower(v)] {
+			m.filled[field]++
+		}
+	}
+}
+
+// Metrics returns the job count and fill-rates of the jobs added so far
+func (m *Meter) Metrics() Metrics {
+	out := Metrics{Jobs: m.jobs, FillRates: make(map[string]float64, len(Fields))}
+	if m.jobs == 0 {
+		return out
+	}
+	for _, f := range Fields {
+		out.FillRates[f] = float64(m.filled[f]) / float64(m.jobs)
+	}
+	return out
+}
+
+// Anomaly is one problem found in a scrape
+type Anomaly struct {
+	Source  string `json:"source"`
+	Message string `json:"message"`
+}
+
+func (a Anomaly) String() string {
+	return a.Source + " " + a.Message
+}
+
+// Drift thresholds relative to the baseline median
+const (
+	countDropRatio  = 0.3 // fewer than 30% of the usual job count
+	fillDropRatio   = 0.5 // a field filled half as often as usual
+	minBaselineFill = 0.2 // ignore fields that were rarely filled to begin with
+)
+
+// Check compares a scrape against the parser's expectations and the rolling baseline
+func Check(source string, m Metrics, exp Expectations, baseline []Metrics) []Anomaly {
+	var out []Anomaly
+	add := func(format string, args ...interface{}) {
+		out = append(out, Anomaly{Source: source, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if m.Jobs < exp.MinJobs {
+		add("returned %d jobs, expected at least %d", m.Jobs, exp.MinJobs)
+	}
+	if len(baseline) > 0 {
+		usual := median(baseline, func(b Metrics) float64 { return float64(b.Jobs) })
+		if usual > 0 && float64(m.Jobs) < usual*countDropRatio {
+			add("job count dropped from %.0f to %d", usual, m.Jobs)
+		}
+	}
+	// Fill-rates are meaningless without jobs; the count checks above already fired
+	if m.Jobs == 0 {
+		return out
+	}
+
+	for _, field := range sortedKeys(exp.FillRates) {
+		if min := exp.FillRates[field]; m.FillRates[field] < min {
+			add("%s fill-rate is %s, expected at least %s", field, pct(m.FillRates[field]), pct(min))
+		}
+	}
+	if len(baseline) > 0 {
+		for _, field := range Fields {
+			usual := median(baseline, func(b Metrics) float64 { return b.FillRates[field] })
+			if usual >= minBaselineFill && m.FillRates[field] < usual*fillDropRatio {
+				add("%s fill-rate dropped from %s to %s", field, pct(usual), pct(m.FillRates[field]))
+			}
+		}
+	}
+	return out
+}
+
+func median(ms []Metrics, value func(Metrics) float64) float64 {
+	vals := make([]float64, len(ms))
+	for i, m := range ms {
+		vals[i] = value(m)
+	}
+	sort.Float64s(vals)
+	mid := len(vals) / 2
+	if len(vals)%2 == 0 {
+		return (vals[mid-1] + vals[mid]) / 2
+	}
+	return vals[mid]
+}
+
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func pct(f float64) string {
+	return fmt.Sprintf("%.0f%%", f*100)
+}
@@ -0,0 +1,140 @@
+package health
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+)
+
+// full is a run where every tracked field is filled
+func full(jobs int) Metrics {
+	m := Metrics{Jobs: jobs, FillRates: make(map[string]float64)}
+	for _, f := range Fields {
+		m.FillRates[f] = 1
+	}
+	return m
+}
+
+func TestMeasure(t *testing.T) {
+	m := Measure([]models.Job{
+		{Title: "Go Engineer", Company: "Acme", Description: "Click to apply on LinkedIn to view full description."},
+		{Title: "SRE", Company: " ", Location: "Berlin", Salary: "$100k"},
+	})
+	want := map[string]float64{"title": 1, "company": 0.5, "location": 0.5, "description": 0, "url": 0, "salary": 0.5}
+	if m.Jobs != 2 || !reflect.DeepEqual(m.FillRates, want) {
+		t.Errorf("Measure = %+v, want 2 jobs and %v", m, want)
+	}
+}
+
+func TestMedian(t *testing.T) {
+	jobs := func(m Metrics) float64 { return float64(m.Jobs) }
+	for _, tc := range []struct {
+		runs []int
+		want float64
+	}{
+		{[]int{7}, 7},
+		{[]int{30, 10, 20}, 20},
+		// Even count: the mean of the middle two
+		{[]int{40, 10, 30, 20}, 25},
+		// One bad run doesn't move it
+		{[]int{100, 0, 100, 100, 100}, 100},
+	} {
+		var ms []Metrics
+		for _, n := range tc.runs {
+			ms = append(ms, Metrics{Jobs: n})
+		}
+		if got := median(ms, jobs); got != tc.want {
+			t.Errorf("median(%v) = %v, want %v", tc.runs, got, tc.want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	halfDescribed := full(90)
+	halfDescribed.FillRates["description"] = 0.4
+	rarelyPaid := full(100)
+	rarelyPaid.FillRates["salary"] = 0.1
+	unpaid := full(100)
+	unpaid.FillRates["salary"] = 0
+
+	for _, tc := range []struct {
+		name     string
+		m        Metrics
+		exp      Expectations
+		baseline []Metrics
+		want     []string
+	}{
+		{"healthy", full(95), Expectations{MinJobs: 10}, []Metrics{full(100), full(90)}, nil},
+		{"no baseline yet", full(3), Expectations{}, nil, nil},
+		{"below minimum", full(3), Expectations{MinJobs: 10}, nil,
+			[]string{"returned 3 jobs, expected at least 10"}},
+		{"count drop", full(20), Expectations{}, []Metrics{full(100), full(90), full(110)},
+			[]string{"job count dropped from 100 to 20"}},
+		{"empty run skips fill-rates", Metrics{}, Expectations{FillRates: map[string]float64{"title": 1}}, []Metrics{full(100)},
+			[]string{"job count dropped from 100 to 0"}},
+		{"expected fill-rate", halfDescribed, Expectations{FillRates: map[string]float64{"description": 0.9}}, nil,
+			[]string{"description fill-rate is 40%, expected at least 90%"}},
+		{"fill-rate drift", halfDescribed, Expectations{}, []Metrics{full(100)},
+			[]string{"description fill-rate dropped from 100% to 40%"}},
+		{"rarely filled fields don't drift", unpaid, Expectations{}, []Metrics{rarelyPaid, rarelyPaid}, nil},
+	} {
+		var got []string
+		for _, a := range Check("Src", tc.m, tc.exp, tc.baseline) {
+			if a.Source != "Src" {
+				t.Errorf("%s: anomaly for %q", tc.name, a.Source)
+			}
+			got = append(got, a.Message)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestBaselineAcceptsLastingChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "health.json")
+	b := NewBaseline(path)
+	at := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		b.Record("Src", full(100), at)
+	}
+
+	// A healthy run in between starts the streak over
+	b.Hold("Src", full(10), at)
+	b.Record("Src", full(100), at)
+	for i := 1; i < acceptAfter; i++ {
+		if b.Hold("Src", full(10), at) {
+			t.Fatalf("accepted after %d anomalous runs", i)
+		}
+	}
+	if got := median(b.Metrics("Src"), func(m Metrics) float64 { return float64(m.Jobs) }); got != 100 {
+		t.Errorf("held runs moved the baseline to %v", got)
+	}
+
+	// The streak survives a reload, and the next one makes the drop the new normal
+	if err := b.Save(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := LoadBaseline(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !b.Hold("Src", full(10), at) {
+		t.Fatalf("not accepted after %d anomalous runs", acceptAfter)
+	}
+	if anomalies := Check("Src", full(12), Expectations{}, b.Metrics("Src")); len(anomalies) != 0 {
+		t.Errorf("still anomalous after the change was accepted: %v", anomalies)
+	}
+	if len(b.Held) != 0 {
+		t.Errorf("held = %v, want none", b.Held)
+	}
+
+	// Accepting by hand takes a single run
+	b.Reset("Src", full(500), at)
+	if got := b.Metrics("Src"); len(got) != 1 || got[0].Jobs != 500 {
+		t.Errorf("after Reset = %+v", got)
+	}
+}
@@ -0,0 +1,34 @@
+package htmltext
+
+import (
+	"html"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Text flattens an HTML fragment (a job description, usually) to plain text.
+// Many APIs entity-escape their HTML a second time, so it is unescaped first.
+func Text(s string) string {
+	s = html.UnescapeString(s)
+	if !strings.Contains(s, "<") {
+		return CollapseSpace(s)
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(s))
+	if err != nil {
+		return CollapseSpace(s)
+	}
+	// Block elements would otherwise run their words together, both with what
+	// follows and with unwrapped text before them (HN comments never close a <p>)
+	doc.Find("br, p, li, div, h1, h2, h3, h4, h5, h6, tr").Each(func(_ int, el *goquery.Selection) {
+		el.PrependHtml(" ")
+		el.AppendHtml(" ")
+	})
+	doc.Find("script, style").Remove()
+	return CollapseSpace(doc.Text())
+}
+
+// CollapseSpace trims s and folds every run of whitespace into one space
+func CollapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
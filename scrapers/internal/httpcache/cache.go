@@ -0,0 +1,168 @@
+package httpcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Mode controls whether the cache is consulted, filled, or both
+type Mode string
+
+const (
+	ModeOff       Mode = "off"
+	ModeRead      Mode = "read"
+	ModeWrite     Mode = "write"
+	ModeReadWrite Mode = "readwrite"
+)
+
+// ParseMode validates a --cache flag value
+func ParseMode(s string) (Mode, error) {
+	switch m := Mode(s); m {
+	case ModeOff, ModeRead, ModeWrite, ModeReadWrite:
+		return m, nil
+	case "":
+		return ModeOff, nil
+	default:
+		return "", fmt.Errorf("invalid cache mode %q (want off, read, write or readwrite)", s)
+	}
+}
+
+// Reads reports whether cached entries may be served
+func (m Mode) Reads() bool { return m == ModeRead || m == ModeReadWrite }
+
+// Writes reports whether fresh responses should be stored
+func (m Mode) Writes() bool { return m == ModeWrite || m == ModeReadWrite }
+
+// MethodRender keys rendered DOM snapshots from headless browsers, which are
+// stored alongside plain HTTP responses but never served to HTTP clients
+const MethodRender = "RENDER"
+
+// Entry is one stored response
+type Entry struct {
+	Method       string      `json:"method"`
+	URL          string      `json:"url"`
+	StatusCode   int         `json:"statusCode"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+	StoredAt     time.Time   `json:"storedAt"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"lastModified,omitempty"`
+}
+
+// Fresh reports whether the entry is younger than ttl; a zero ttl never expires
+func (e *Entry) Fresh(ttl time.Duration, now time.Time) bool {
+	return ttl == 0 || now.Sub(e.StoredAt) < ttl
+}
+
+// Cache stores responses on disk, one JSON file per method+URL
+type Cache struct {
+	Dir  string
+	TTL  time.Duration
+	Mode Mode
+}
+
+// New returns a cache rooted at dir
+func New(dir string, ttl time.Duration, mode Mode) *Cache {
+	return &Cache{Dir: dir, TTL: ttl, Mode: mode}
+}
+
+// Key is the file name used for a method+URL pair
+func Key(method, url string) string {
+	sum := sha256.Sum256([]byte(method + " " + url))
+	return hex.EncodeToString(sum[:])
+}
+
+// Get loads the entry for method+url; ok is false when nothing is stored
+func (c *Cache) Get(method, url string) (e *Entry, ok bool) {
+	data, err := os.ReadFile(c.path(method, url))
+	if err != nil {
+		return nil, false
+	}
+	e = &Entry{}
+	if err := json.Unmarshal(data, e); err != nil {
+		return nil, false
+	}
+	return e, true
+}
+
+// Put stores an entry, stamping it with the current time if StoredAt is unset
+func (c *Cache) Put(e *Entry) error {
+	if e.StoredAt.IsZero() {
+		e.StoredAt = time.Now()
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %v", err)
+	}
+
+	path := c.path(e.Method, e.URL)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache dir: %v", err)
+	}
+	// Write then rename so a concurrent reader never sees half an entry
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write cache entry: %v", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// path shards entries by the first two hex chars to keep directories small
+func (c *Cache) path(method, url string) string {
+	key := Key(method, url)
+	return filepath.Join(c.Dir, key[:2], key+".json")
+}
+
+var (
+	defaultMu    sync.RWMutex
+	defaultCache *Cache
+)
+
+// SetDefault installs the process-wide cache used by the fetcher and headless parsers.
+// Passing nil, or a cache in ModeOff, disables caching.
+func SetDefault(c *Cache) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if c != nil && c.Mode == ModeOff {
+		c = nil
+	}
+	defaultCache = c
+}
+
+// Default returns the process-wide cache, or nil when caching is off
+func Default() *Cache {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultCache
+}
+
+// FromEnv builds a cache from CACHE_MODE, CACHE_DIR and CACHE_TTL.
+// mode, when non-empty, overrides CACHE_MODE (it comes from the --cache flag).
+func FromEnv(mode string) (*Cache, error) {
+	if mode == "" {
+		mode = os.Getenv("CACHE_MODE")
+	}
+	m, err := ParseMode(mode)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := os.Getenv("CACHE_DIR")
+	if dir == "" {
+		dir = "data/cache"
+	}
+	ttl := 24 * time.Hour
+	if v := os.Getenv("CACHE_TTL"); v != "" {
+		if ttl, err = time.ParseDuration(v); err != nil {
+			return nil, fmt.Errorf("invalid CACHE_TTL %q: %v", v, err)
+		}
+	}
+	return New(dir, ttl, m), nil
+}
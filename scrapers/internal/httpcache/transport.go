@@ -0,0 +1,109 @@
+package httpcache
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Transport serves and stores GET/HEAD responses through a Cache
+type Transport struct {
+	Cache *Cache
+	Base  http.RoundTripper
+}
+
+// Transport wraps base (http.DefaultTransport when nil) with this cache
+func (c *Cache) Transport(base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{Cache: c, Base: base}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return t.Base.RoundTrip(req)
+	}
+
+	url := req.URL.String()
+	entry, cached := t.Cache.Get(req.Method, url)
+	if cached && !t.Cache.Mode.Reads() {
+		cached = false
+	}
+
+	if cached && entry.Fresh(t.Cache.TTL, time.Now()) {
+		fmt.Printf("💾 Cache hit %s %s\n", req.Method, url)
+		return entry.response(req), nil
+	}
+
+	// Stale but revalidatable: ask the server whether our copy is still good
+	if cached && (entry.ETag != "" || entry.LastModified != "") {
+		req = req.Clone(req.Context())
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		fmt.Printf("💾 Cache revalidated %s %s\n", req.Method, url)
+		if t.Cache.Mode.Writes() {
+			entry.StoredAt = time.Now()
+			if err := t.Cache.Put(entry); err != nil {
+				fmt.Printf("⚠️  %v\n", err)
+			}
+		}
+		return entry.response(req), nil
+	}
+
+	if !t.Cache.Mode.Writes() || resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	err = t.Cache.Put(&Entry{
+		Method:       req.Method,
+		URL:          url,
+		StatusCode:   resp.StatusCode,
+		Header:       resp.Header.Clone(),
+		Body:         body,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	})
+	if err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	}
+	return resp, nil
+}
+
+// response rebuilds an *http.Response for req from the stored entry
+func (e *Entry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
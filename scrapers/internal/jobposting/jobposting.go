@@ -0,0 +1,449 @@
+package jobposting
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/groot34/job-aggregator/scraper/internal/htmltext"
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+)
+
+// Posting is a schema.org JobPosting reduced to the fields we map onto models.Job
+type Posting struct {
+	Identifier   string
+	Title        string
+	Company      string
+	Description  string
+	URL          string
+	Locations    []string
+	Remote       bool
+	Salary       string
+	Skills       []string
+	DatePosted   time.Time // zero when the page doesn't say
+	ValidThrough time.Time // zero when the page doesn't say
+}
+
+// Location joins every listed place the way the other parsers format multiple locations
+func (p Posting) Location() string {
+	return strings.Join(p.Locations, " / ")
+}
+
+// Expired reports whether the posting's validThrough date has passed
+func (p Posting) Expired(now time.Time) bool {
+	return !p.ValidThrough.IsZero() && p.ValidThrough.Before(now)
+}
+
+// Job converts the posting; idPrefix and source identify the parser that found it.
+// Postings without an identifier get a hash of their URL and title, since
+// several of them can share one careers page URL.
+func (p Posting) Job(source, idPrefix string) models.Job {
+	id := p.Identifier
+	if id == "" {
+		sum := sha1.Sum([]byte(p.URL + "|" + p.Title))
+		id = hex.EncodeToString(sum[:6])
+	}
+	postedAt := p.DatePosted
+	if postedAt.IsZero() {
+		postedAt = time.Now()
+	}
+	return models.Job{
+		ID:          idPrefix + "-" + id,
+		Title:       p.Title,
+		Company:     p.Company,
+		Location:    p.Location(),
+		Description: p.Description,
+		URL:         p.URL,
+		Source:      source,
+		PostedAt:    postedAt,
+		ScrapedAt:   time.Now(),
+		Remote:      p.Remote,
+		Salary:      p.Salary,
+		Tags:        p.Skills,
+	}
+}
+
+// Fill completes a job scraped from a listing with what its detail page says.
+// Fields the listing already had are kept; the posting's date wins since listings
+// rarely carry one.
+func (p Posting) Fill(j *models.Job) {
+	fill := func(dst *string, v string) {
+		if strings.TrimSpace(*dst) == "" || strings.EqualFold(*dst, "unknown") {
+			*dst = v
+		}
+	}
+	fill(&j.Company, p.Company)
+	fill(&j.Location, p.Location())
+	fill(&j.Description, p.Description)
+	fill(&j.Salary, p.Salary)
+	if !p.DatePosted.IsZero() {
+		j.PostedAt = p.DatePosted
+	}
+	j.Remote = j.Remote || p.Remote
+	for _, skill := range p.Skills {
+		if !containsFold(j.Tags, skill) {
+			j.Tags = append(j.Tags, skill)
+		}
+	}
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Extract finds every JobPosting on a page, from JSON-LD scripts first and
+// microdata second. pageURL fills in postings that don't state their own URL.
+func Extract(page, pageURL string) ([]Posting, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse page: %v", err)
+	}
+
+	var objects []map[string]interface{}
+	doc.Find(`script[type="application/ld+json"]`).Each(func(_ int, s *goquery.Selection) {
+		var v interface{}
+		// Pages in the wild often have trailing commas or several documents; skip what won't parse
+		if err := json.Unmarshal([]byte(s.Text()), &v); err == nil {
+			objects = append(objects, jobPostings(v)...)
+		}
+	})
+	doc.Find(`[itemscope][itemtype]`).Each(func(_ int, s *goquery.Selection) {
+		if isJobPostingType(s.AttrOr("itemtype", "")) {
+			objects = append(objects, microdata(s))
+		}
+	})
+
+	var postings []Posting
+	for _, obj := range objects {
+		if p, ok := fromObject(obj, pageURL); ok {
+			postings = append(postings, p)
+		}
+	}
+	return postings, nil
+}
+
+// jobPostings collects JobPosting objects from a JSON-LD document, including
+// ones nested in arrays, @graph or ItemList elements
+func jobPostings(v interface{}) []map[string]interface{} {
+	var found []map[string]interface{}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if isJobPosting(v) {
+			return []map[string]interface{}{v}
+		}
+		for _, key := range []string{"@graph", "itemListElement", "item", "mainEntity"} {
+			if child, ok := v[key]; ok {
+				found = append(found, jobPostings(child)...)
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			found = append(found, jobPostings(child)...)
+		}
+	}
+	return found
+}
+
+func isJobPosting(obj map[string]interface{}) bool {
+	for _, t := range list(obj["@type"]) {
+		if s, ok := t.(string); ok && isJobPostingType(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// isJobPostingType accepts "JobPosting" as well as full schema.org type URLs
+func isJobPostingType(t string) bool {
+	t = strings.TrimSuffix(strings.TrimSpace(t), "/")
+	return t == "JobPosting" || strings.HasSuffix(t, "schema.org/JobPosting")
+}
+
+// microdata turns an itemscope element into the same shape JSON-LD decodes to
+func microdata(s *goquery.Selection) map[string]interface{} {
+	obj := map[string]interface{}{"@type": itemType(s.AttrOr("itemtype", ""))}
+	s.Find("[itemprop]").Each(func(_ int, prop *goquery.Selection) {
+		// Only direct properties: skip those that belong to a nested item
+		if owner := prop.ParentsFiltered("[itemscope]").First(); owner.Length() == 0 || !owner.IsSelection(s) {
+			return
+		}
+		var value interface{}
+		if _, nested := prop.Attr("itemscope"); nested {
+			value = microdata(prop)
+		} else {
+			value = propValue(prop)
+		}
+		for _, name := range strings.Fields(prop.AttrOr("itemprop", "")) {
+			if prev, ok := obj[name]; ok {
+				if list, ok := prev.([]interface{}); ok {
+					obj[name] = append(list, value)
+				} else {
+					obj[name] = []interface{}{prev, value}
+				}
+			} else {
+				obj[name] = value
+			}
+		}
+	})
+	return obj
+}
+
+func itemType(t string) string {
+	t = strings.TrimSuffix(strings.TrimSpace(t), "/")
+	return t[strings.LastIndex(t, "/")+1:]
+}
+
+// propValue reads a microdata property the way the spec says each element carries it
+func propValue(s *goquery.Selection) string {
+	for _, attr := range []string{"content", "datetime"} {
+		if v, ok := s.Attr(attr); ok {
+			return strings.TrimSpace(v)
+		}
+	}
+	switch goquery.NodeName(s) {
+	case "a", "link", "area":
+		return s.AttrOr("href", "")
+	case "img", "audio", "video", "source":
+		return s.AttrOr("src", "")
+	case "meta":
+		return ""
+	}
+	if _, ok := s.Attr("value"); ok {
+		return s.AttrOr("value", "")
+	}
+	return htmltext.CollapseSpace(s.Text())
+}
+
+func fromObject(obj map[string]interface{}, pageURL string) (Posting, bool) {
+	p := Posting{
+		Identifier:  identifier(obj["identifier"]),
+		Title:       firstString(obj, "title", "name"),
+		Company:     name(obj["hiringOrganization"]),
+		Description: htmltext.Text(str(obj["description"])),
+		URL:         str(obj["url"]),
+		Salary:      salary(obj["baseSalary"]),
+		Skills:      strs(obj["skills"]),
+		DatePosted:  parseDate(str(obj["datePosted"])),
+	}
+	if p.Title == "" {
+		return p, false
+	}
+	if p.URL == "" {
+		p.URL = pageURL
+	}
+	p.ValidThrough = parseDate(str(obj["validThrough"]))
+
+	for _, place := range list(obj["jobLocation"]) {
+		if loc := location(place); loc != "" {
+			p.Locations = append(p.Locations, loc)
+		}
+	}
+	if strings.EqualFold(str(obj["jobLocationType"]), "TELECOMMUTE") {
+		p.Remote = true
+	}
+	for _, loc := range p.Locations {
+		if strings.Contains(strings.ToLower(loc), "remote") {
+			p.Remote = true
+		}
+	}
+	if p.Remote && len(p.Locations) == 0 {
+		p.Locations = []string{"Remote"}
+	}
+	return p, true
+}
+
+// location formats a Place, PostalAddress or plain string as "City, Region, Country"
+func location(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case map[string]interface{}:
+		if addr, ok := v["address"]; ok {
+			return location(addr)
+		}
+		var parts []string
+		for _, key := range []string{"addressLocality", "addressRegion", "addressCountry"} {
+			if part := name(v[key]); part != "" {
+				parts = append(parts, part)
+			}
+		}
+		if len(parts) == 0 {
+			return name(v)
+		}
+		return strings.Join(parts, ", ")
+	}
+	return ""
+}
+
+// salary formats a MonetaryAmount as e.g. "$120K - $150K" or "₹8L - ₹12L"
+func salary(v interface{}) string {
+	amount, ok := v.(map[string]interface{})
+	if !ok {
+		if n, ok := number(v); ok {
+			return formatAmount("", n)
+		}
+		return str(v)
+	}
+
+	currency := str(amount["currency"])
+	value := amount["value"]
+	unit := ""
+	var min, max float64
+	if qv, ok := value.(map[string]interface{}); ok {
+		unit = str(qv["unitText"])
+		min, _ = number(qv["minValue"])
+		max, _ = number(qv["maxValue"])
+		if n, ok := number(qv["value"]); ok && min == 0 && max == 0 {
+			min = n
+		}
+	} else if n, ok := number(value); ok {
+		min = n
+	}
+	return FormatSalary(currency, min, max, unit)
+}
+
+// FormatSalary writes a pay range as e.g. "$120K - $150K", "₹8L - ₹12L" or
+// "$35 /hour"; unit is the pay period and is omitted for yearly pay
+func FormatSalary(currency string, min, max float64, unit string) string {
+	var s string
+	switch {
+	case min > 0 && max > min:
+		s = formatAmount(currency, min) + " - " + formatAmount(currency, max)
+	case min > 0:
+		s = formatAmount(currency, min)
+	case max > 0:
+		s = formatAmount(currency, max)
+	default:
+		return ""
+	}
+	if unit = strings.ToLower(unit); unit != "" && unit != "year" {
+		s += " /" + unit
+	}
+	return s
+}
+
+var currencySymbols = map[string]string{"USD": "$", "EUR": "€", "GBP": "£", "INR": "₹", "CAD": "CA$", "AUD": "A$"}
+
+// formatAmount writes large amounts compactly in the units the salary parser understands
+func formatAmount(currency string, n float64) string {
+	prefix, ok := currencySymbols[strings.ToUpper(currency)]
+	if !ok && currency != "" {
+		prefix = strings.ToUpper(currency) + " "
+	}
+	switch {
+	case strings.EqualFold(currency, "INR") && n >= 100_000:
+		return prefix + trimFloat(n/100_000) + "L"
+	case n >= 1_000_000:
+		return prefix + trimFloat(n/1_000_000) + "M"
+	case n >= 1_000:
+		return prefix + trimFloat(n/1_000) + "K"
+	default:
+		return prefix + trimFloat(n)
+	}
+}
+
+func trimFloat(n float64) string {
+	return strconv.FormatFloat(math.Round(n*10)/10, 'f', -1, 64)
+}
+
+// dateLayouts covers the ISO 8601 variants seen in datePosted and validThrough
+var dateLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02"}
+
+func parseDate(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// identifier reads a PropertyValue, a plain string or a number
+func identifier(v interface{}) string {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if id := identifier(v["value"]); id != "" {
+			return id
+		}
+		return firstString(v, "@id", "name")
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return str(v)
+}
+
+// name reads an Organization/Country-like object or a plain string
+func name(v interface{}) string {
+	if obj, ok := v.(map[string]interface{}); ok {
+		return firstString(obj, "name", "legalName")
+	}
+	return str(v)
+}
+
+func firstString(obj map[string]interface{}, keys ...string) string {
+	for _, k := range keys {
+		if s := str(obj[k]); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+func str(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case []interface{}:
+		if len(v) > 0 {
+			return str(v[0])
+		}
+	}
+	return ""
+}
+
+// strs accepts a list or a comma-separated string, as skills appear in both forms
+func strs(v interface{}) []string {
+	var out []string
+	for _, item := range list(v) {
+		for _, s := range strings.Split(str(item), ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
+
+func list(v interface{}) []interface{} {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		return v
+	default:
+		return []interface{}{v}
+	}
+}
+
+func number(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case string:
+		n, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(v), ",", ""), 64)
+		return n, err == nil
+	}
+	return 0, false
+}
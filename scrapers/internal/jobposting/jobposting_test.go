@@ -0,0 +1,125 @@
+package jobposting
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFormatSalary(t *testing.T) {
+	for _, tc := range []struct {
+		currency string
+		min, max float64
+		unit     string
+		want     string
+	}{
+		{"USD", 120000, 150000, "YEAR", "$120K - $150K"},
+		{"INR", 800000, 1200000, "", "₹8L - ₹12L"},
+		{"USD", 35, 0, "HOUR", "$35 /hour"},
+		{"EUR", 0, 90000, "", "€90K"},
+		{"USD", 1500000, 1500000, "", "$1.5M"},
+		{"SEK", 45000, 55000, "MONTH", "SEK 45K - SEK 55K /month"},
+		{"", 2500, 0, "", "2.5K"},
+		{"USD", 0, 0, "YEAR", ""},
+	} {
+		if got := FormatSalary(tc.currency, tc.min, tc.max, tc.unit); got != tc.want {
+			t.Errorf("FormatSalary(%q, %v, %v, %q) = %q, want %q", tc.currency, tc.min, tc.max, tc.unit, got, tc.want)
+		}
+	}
+}
+
+func TestExtract(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	for _, tc := range []struct {
+		name, page string
+		want       []Posting
+	}{
+		{
+			name: "json-ld salary range and dates",
+			page: `<script type="application/ld+json">{
+				"@context": "https://schema.org", "@type": "JobPosting",
+				"identifier": {"@type": "PropertyValue", "value": 42},
+				"title": "Go Engineer", "description": "<p>Build <b>APIs</b></p>",
+				"hiringOrganization": {"@type": "Organization", "name": "Acme"},
+				"datePosted": "2024-03-01T09:30:00Z", "validThrough": "2024-04-01",
+				"jobLocation": {"@type": "Place", "address": {"addressLocality": "Berlin", "addressCountry": "DE"}},
+				"baseSalary": {"@type": "MonetaryAmount", "currency": "EUR",
+					"value": {"@type": "QuantitativeValue", "minValue": "70,000", "maxValue": 90000, "unitText": "YEAR"}},
+				"skills": "Go, PostgreSQL"
+			}</script>`,
+			want: []Posting{{
+				Identifier: "42", Title: "Go Engineer", Company: "Acme", Description: "Build APIs",
+				URL: "https://example.com/careers", Locations: []string{"Berlin, DE"},
+				Salary: "€70K - €90K", Skills: []string{"Go", "PostgreSQL"},
+				DatePosted:   time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC),
+				ValidThrough: date(2024, 4, 1),
+			}},
+		},
+		{
+			name: "graph with a single hourly value and remote work",
+			page: `<script type="application/ld+json">{"@graph": [
+				{"@type": "Organization", "name": "Acme"},
+				{"@type": "https://schema.org/JobPosting", "name": "Support Agent", "url": "https://example.com/jobs/7",
+				 "jobLocationType": "TELECOMMUTE", "datePosted": "2024-03-05",
+				 "baseSalary": {"currency": "USD", "value": {"value": 35, "unitText": "HOUR"}}}
+			]}</script>`,
+			want: []Posting{{
+				Title: "Support Agent", URL: "https://example.com/jobs/7", Locations: []string{"Remote"},
+				Remote: true, Salary: "$35 /hour", DatePosted: date(2024, 3, 5),
+			}},
+		},
+		{
+			name: "unparseable json-ld and untitled postings are skipped",
+			page: `<script type="application/ld+json">{"@type": "JobPosting", "title": "Broken",}</script>
+				<script type="application/ld+json">{"@type": "JobPosting", "datePosted": "2024-03-05"}</script>`,
+		},
+		{
+			name: "microdata with nested items",
+			page: `<div itemscope itemtype="https://schema.org/JobPosting">
+				<h1 itemprop="title">Data Engineer</h1>
+				<div itemprop="hiringOrganization" itemscope itemtype="https://schema.org/Organization">
+					<span itemprop="name">Globex</span>
+				</div>
+				<time itemprop="datePosted" datetime="2024-02-10">10 Feb</time>
+				<meta itemprop="validThrough" content="2024-03-10T00:00">
+				<div itemprop="jobLocation" itemscope itemtype="https://schema.org/Place">
+					<div itemprop="address" itemscope itemtype="https://schema.org/PostalAddress">
+						<span itemprop="addressLocality">Pune</span>, <span itemprop="addressRegion">Maharashtra</span>
+					</div>
+				</div>
+				<div itemprop="description">  Pipelines   and warehouses </div>
+				<a itemprop="url" href="https://example.com/jobs/data">Apply</a>
+			</div>`,
+			want: []Posting{{
+				Title: "Data Engineer", Company: "Globex", Description: "Pipelines and warehouses",
+				URL: "https://example.com/jobs/data", Locations: []string{"Pune, Maharashtra"},
+				DatePosted: date(2024, 2, 10), ValidThrough: time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+			}},
+		},
+	} {
+		got, err := Extract(tc.page, "https://example.com/careers")
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s:\ngot  %+v\nwant %+v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestExpired(t *testing.T) {
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		validThrough time.Time
+		want         bool
+	}{
+		{time.Time{}, false},
+		{now.Add(-time.Hour), true},
+		{now.Add(time.Hour), false},
+	} {
+		if got := (Posting{ValidThrough: tc.validThrough}).Expired(now); got != tc.want {
+			t.Errorf("Expired with validThrough %v = %v, want %v", tc.validThrough, got, tc.want)
+		}
+	}
+}
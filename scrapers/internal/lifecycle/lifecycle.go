@@ -0,0 +1,227 @@
+package lifecycle
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+)
+
+// Status describes a change in a job's lifecycle between two runs
+type Status string
+
+const (
+	StatusNew      Status = "new"
+	StatusUpdated  Status = "updated"
+	StatusClosed   Status = "closed"
+	StatusReopened Status = "reopened"
+)
+
+// Record is what we remember about a job ID across runs
+type Record struct {
+	ID        string     `json:"externalId"`
+	Title     string     `json:"title"`
+	Company   string     `json:"company"`
+	Source    string     `json:"source"`
+	Board     string     `json:"board,omitempty"` // board or page within Source it was last listed on
+	FirstSeen time.Time  `json:"firstSeen"`
+	LastSeen  time.Time  `json:"lastSeen"`
+	ClosedAt  *time.Time `json:"closedAt,omitempty"`
+	Hash      string     `json:"hash"`
+}
+
+// Open reports whether the job was still listed on its last observed run
+func (r Record) Open() bool {
+	return r.ClosedAt == nil
+}
+
+// Event is a status change that publishers can forward downstream
+type Event struct {
+	JobID   string      `json:"externalId"`
+	Status  Status      `json:"status"`
+	Source  string      `json:"source"`
+	Company string      `json:"company"`
+	Title   string      `json:"title"`
+	At      time.Time   `json:"at"`
+	Job     *models.Job `json:"job,omitempty"` // nil for closed jobs
+}
+
+// Scrape is one source's outcome in a run. Its jobs on the Failed boards stay
+// open; every other open job of the source that the run didn't list is closed.
+type Scrape struct {
+	Source string
+	Failed []string
+}
+
+// Tracker keeps per-job lifecycle records and persists them as JSON
+type Tracker struct {
+	path    string
+	mu      sync.Mutex
+	records map[string]*Record
+}
+
+// Path returns LIFECYCLE_STATE_PATH, defaulting to data/lifecycle.json
+func Path() string {
+	if p := os.Getenv("LIFECYCLE_STATE_PATH"); p != "" {
+		return p
+	}
+	return "data/lifecycle.json"
+}
+
+// Load reads the tracker state from path. A missing file yields an empty tracker.
+func Load(path string) (*Tracker, error) {
+	t := &Tracker{path: path, records: make(map[string]*Record)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return t, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lifecycle state: %v", err)
+	}
+
+	var records []*Record
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to decode lifecycle state: %v", err)
+	}
+	for _, r := range records {
+		t.records[r.ID] = r
+	}
+	return t, nil
+}
+
+// Save writes the tracker state back to the path it was loaded from
+func (t *Tracker) Save() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	records := make([]*Record, 0, len(t.records))
+	for _, r := range t.records {
+		records = append(records, r)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode lifecycle state: %v", err)
+	}
+	if dir := filepath.Dir(t.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create lifecycle dir: %v", err)
+		}
+	}
+	if err := os.WriteFile(t.path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write lifecycle state: %v", err)
+	}
+	return nil
+}
+
+// Observe applies one run's results to the tracker and returns the resulting events.
+// Jobs missing from a source listed in scraped are marked closed unless they
+// were on one of its failed boards; sources that were not scraped (e.g. because
+// they failed outright) leave their open jobs untouched.
+func (t *Tracker) Observe(jobs []models.Job, scraped []Scrape, now time.Time) []Event {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var events []Event
+	seen := make(map[string]bool, len(jobs))
+
+	for i := range jobs {
+		j := jobs[i]
+		if j.ID == "" || seen[j.ID] {
+			continue
+		}
+		seen[j.ID] = true
+		hash := j.ContentHash()
+
+		r, ok := t.records[j.ID]
+		switch {
+		case !ok:
+			r = &Record{ID: j.ID, FirstSeen: now, Hash: hash}
+			t.records[j.ID] = r
+			events = append(events, newEvent(StatusNew, &j, now))
+		case !r.Open():
+			r.ClosedAt = nil
+			r.Hash = hash
+			events = append(events, newEvent(StatusReopened, &j, now))
+		case r.Hash != hash:
+			r.Hash = hash
+			events = append(events, newEvent(StatusUpdated, &j, now))
+		}
+
+		r.Title = j.Title
+		r.Company = j.Company
+		r.Source = j.Source
+		r.Board = j.Board
+		r.LastSeen = now
+	}
+
+	failed := make(map[string]map[string]bool, len(scraped))
+	for _, s := range scraped {
+		failed[s.Source] = make(map[string]bool, len(s.Failed))
+		for _, b := range s.Failed {
+			failed[s.Source][b] = true
+		}
+	}
+	for _, r := range t.records {
+		if seen[r.ID] || !r.Open() || !closable(r, failed) {
+			continue
+		}
+		closedAt := now
+		r.ClosedAt = &closedAt
+		events = append(events, Event{
+			JobID:   r.ID,
+			Status:  StatusClosed,
+			Source:  r.Source,
+			Company: r.Company,
+			Title:   r.Title,
+			At:      now,
+		})
+	}
+
+	return events
+}
+
+// closable reports whether r's source was scraped and the board r was on didn't
+// fail. A record with no board is only closed when none of its source's did.
+func closable(r *Record, failed map[string]map[string]bool) bool {
+	boards, ok := failed[r.Source]
+	if !ok {
+		return false
+	}
+	if r.Board == "" {
+		return len(boards) == 0
+	}
+	return !boards[r.Board]
+}
+
+// Records returns a snapshot of all tracked records, ordered by ID
+func (t *Tracker) Records() []Record {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	out := make([]Record, 0, len(t.records))
+	for _, r := range t.records {
+		out = append(out, *r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+func newEvent(status Status, j *models.Job, now time.Time) Event {
+	return Event{
+		JobID:   j.ID,
+		Status:  status,
+		Source:  j.Source,
+		Company: j.Company,
+		Title:   j.Title,
+		At:      now,
+		Job:     j,
+	}
+}
@@ -0,0 +1,140 @@
+package lifecycle
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+)
+
+func job(id, source, title string) models.Job {
+	return models.Job{ID: id, Source: source, Title: title, Company: "Acme"}
+}
+
+// scraped lists sources that completed without failed boards
+func scraped(sources ...string) []Scrape {
+	var out []Scrape
+	for _, s := range sources {
+		out = append(out, Scrape{Source: s})
+	}
+	return out
+}
+
+// statuses maps job ID to the status of its event
+func statuses(events []Event) map[string]Status {
+	out := make(map[string]Status, len(events))
+	for _, e := range events {
+		out[e.JobID] = e.Status
+	}
+	return out
+}
+
+func TestObserveTransitions(t *testing.T) {
+	tracker, err := Load(filepath.Join(t.TempDir(), "lifecycle.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := func(n int) time.Time { return time.Date(2024, 3, n, 9, 0, 0, 0, time.UTC) }
+
+	// Run 1: everything is new
+	events := tracker.Observe([]models.Job{job("a", "LinkedIn", "Go"), job("b", "LinkedIn", "Rust"), job("c", "Lever", "SRE")},
+		scraped("LinkedIn", "Lever"), day(1))
+	if got, want := statuses(events), map[string]Status{"a": StatusNew, "b": StatusNew, "c": StatusNew}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("run 1: %v, want %v", got, want)
+	}
+
+	// Run 2: a changes, b disappears, and Lever failed so c must stay open
+	events = tracker.Observe([]models.Job{job("a", "LinkedIn", "Go (Senior)")}, scraped("LinkedIn"), day(3))
+	if got, want := statuses(events), map[string]Status{"a": StatusUpdated, "b": StatusClosed}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("run 2: %v, want %v", got, want)
+	}
+
+	// Run 3: b comes back, a is unchanged
+	events = tracker.Observe([]models.Job{job("a", "LinkedIn", "Go (Senior)"), job("b", "LinkedIn", "Rust")},
+		scraped("LinkedIn"), day(4))
+	if got, want := statuses(events), map[string]Status{"b": StatusReopened}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("run 3: %v, want %v", got, want)
+	}
+
+	for _, r := range tracker.Records() {
+		if !r.Open() {
+			t.Errorf("%s still closed after run 3", r.ID)
+		}
+		if r.ID == "c" && !r.LastSeen.Equal(day(1)) {
+			t.Errorf("c last seen %v, want %v", r.LastSeen, day(1))
+		}
+	}
+}
+
+func TestObserveKeepsFailedBoardsOpen(t *testing.T) {
+	tracker, err := Load(filepath.Join(t.TempDir(), "lifecycle.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := func(n int) time.Time { return time.Date(2024, 3, n, 9, 0, 0, 0, time.UTC) }
+	onBoard := func(id, board string) models.Job {
+		j := job(id, "Greenhouse", "Go")
+		j.Board = board
+		return j
+	}
+
+	tracker.Observe([]models.Job{onBoard("a", "acme"), onBoard("b", "globex"), onBoard("c", "globex")}, scraped("Greenhouse"), day(1))
+
+	// Globex's board failed: its jobs stay open while Acme's missing one closes
+	events := tracker.Observe(nil, []Scrape{{Source: "Greenhouse", Failed: []string{"globex"}}}, day(2))
+	if got, want := statuses(events), map[string]Status{"a": StatusClosed}; !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %v, want only a closed", got)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "lifecycle.json")
+	tracker, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	tracker.Observe([]models.Job{job("a", "LinkedIn", "Go")}, scraped("LinkedIn"), now)
+	if err := tracker.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A job seen again after a reload is neither new nor updated
+	if events := reloaded.Observe([]models.Job{job("a", "LinkedIn", "Go")}, scraped("LinkedIn"), now.Add(time.Hour)); len(events) != 0 {
+		t.Errorf("events after reload = %v, want none", events)
+	}
+}
+
+func TestStats(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	closed := func(id, source string, days int) *Record {
+		at := start.AddDate(0, 0, days)
+		return &Record{ID: id, Source: source, FirstSeen: start, ClosedAt: &at}
+	}
+	tracker := &Tracker{records: map[string]*Record{
+		"a": closed("a", "LinkedIn", 2),
+		"b": closed("b", "LinkedIn", 10),
+		"c": closed("c", "LinkedIn", 4),
+		"d": closed("d", "LinkedIn", 6),
+		"e": {ID: "e", Source: "LinkedIn", FirstSeen: start},
+		"f": closed("f", "Lever", 3),
+		"g": {ID: "g", Source: "HackerNews", FirstSeen: start},
+	}}
+	day := 24 * time.Hour
+
+	want := []Stat{
+		{Key: "HackerNews", Open: 1},
+		{Key: "Lever", Closed: 1, AvgTimeToFill: 3 * day, MedianTimeToFill: 3 * day, MaxTimeToFill: 3 * day},
+		// Even count: the median is the mean of 4 and 6 days
+		{Key: "LinkedIn", Open: 1, Closed: 4, AvgTimeToFill: 11 * day / 2, MedianTimeToFill: 5 * day, MaxTimeToFill: 10 * day},
+	}
+	if got := tracker.StatsBySource(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+}
@@ -0,0 +1,76 @@
+package lifecycle
+
+import (
+	"sort"
+	"time"
+)
+
+// Stat summarises how long postings stay listed for one group of jobs
+type Stat struct {
+	Key    string `json:"key"`
+	Open   int    `json:"open"`
+	Closed int    `json:"closed"`
+	// Time-to-fill is measured from first-seen to closed-at, so it only covers closed jobs
+	AvgTimeToFill    time.Duration `json:"avgTimeToFill"`
+	MedianTimeToFill time.Duration `json:"medianTimeToFill"`
+	MaxTimeToFill    time.Duration `json:"maxTimeToFill"`
+}
+
+// StatsBySource groups lifecycle stats per source
+func (t *Tracker) StatsBySource() []Stat {
+	return t.StatsBy(func(r Record) string { return r.Source })
+}
+
+// StatsByCompany groups lifecycle stats per company
+func (t *Tracker) StatsByCompany() []Stat {
+	return t.StatsBy(func(r Record) string { return r.Company })
+}
+
+// StatsBy groups records with key and computes time-to-fill stats for each group
+func (t *Tracker) StatsBy(key func(Record) string) []Stat {
+	groups := make(map[string][]Record)
+	for _, r := range t.Records() {
+		k := key(r)
+		groups[k] = append(groups[k], r)
+	}
+
+	stats := make([]Stat, 0, len(groups))
+	for k, records := range groups {
+		stats = append(stats, computeStat(k, records))
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Key < stats[j].Key })
+	return stats
+}
+
+func computeStat(key string, records []Record) Stat {
+	s := Stat{Key: key}
+	var durations []time.Duration
+
+	for _, r := range records {
+		if r.Open() {
+			s.Open++
+			continue
+		}
+		s.Closed++
+		durations = append(durations, r.ClosedAt.Sub(r.FirstSeen))
+	}
+	if len(durations) == 0 {
+		return s
+	}
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+	s.AvgTimeToFill = total / time.Duration(len(durations))
+	s.MaxTimeToFill = durations[len(durations)-1]
+
+	mid := len(durations) / 2
+	if len(durations)%2 == 0 {
+		s.MedianTimeToFill = (durations[mid-1] + durations[mid]) / 2
+	} else {
+		s.MedianTimeToFill = durations[mid]
+	}
+	return s
+}
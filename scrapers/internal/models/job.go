@@ -1,6 +1,11 @@
 package models
 
-import "time"
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
 
 // Job represents a standardized job listing
 type Job struct {
@@ -16,4 +21,22 @@ type Job struct {
 	Remote      bool      `json:"remote"`
 	Salary      string    `json:"salary,omitempty"`
 	Tags        []string  `json:"tags,omitempty"`
+	Department  string    `json:"department,omitempty"`
+	CompanyID   string    `json:"companyId,omitempty"` // company.Directory record; not part of the content hash
+	Board       string    `json:"-"`                   // board or page within Source it was listed on, for sources with several
+}
+
+// ContentHash fingerprints the fields whose change counts as an update.
+// Timestamps and derived tags are left out so reruns don't look like edits.
+func (j Job) ContentHash() string {
+	h := sha1.New()
+	for _, f := range []string{j.Title, j.Company, j.Location, j.Description, j.URL, j.Salary, strconv.FormatBool(j.Remote)} {
+		h.Write([]byte(f))
+		h.Write([]byte{0})
+	}
+	// Only hashed when set, so jobs from sources without departments keep their old hashes
+	if j.Department != "" {
+		h.Write([]byte(j.Department))
+	}
+	return hex.EncodeToString(h.Sum(nil))
 }
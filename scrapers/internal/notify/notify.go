@@ -0,0 +1,217 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+	"github.com/groot34/job-aggregator/scraper/internal/search"
+)
+
+// Report summarises one Notify call
+type Report struct {
+	Sent        int
+	Duplicates  int // already alerted on a previous run
+	RateLimited int // held back by a rule's rate limit, retried next run
+	Failed      int // retried next run
+	Retried     int // held back or failed on an earlier run, sent now
+	Dropped     int // the rule's template can't render the job; never retried
+}
+
+// maxPending bounds how many held-back alerts a rule keeps for later runs;
+// past it the oldest are dropped
+const maxPending = 500
+
+// sentRetention is how long a sent alert is remembered. Notify only ever sees
+// a job on the run lifecycle first tracks it, so the record just has to outlast
+// the retries of held-back alerts and the odd lifecycle state reset.
+const sentRetention = 90 * 24 * time.Hour
+
+// state is persisted between runs so alerts are de-duplicated, rate limits
+// hold across process restarts and alerts that could not go out are retried.
+// Every map is keyed by rule (search + URL).
+type state struct {
+	Sent    map[string]map[string]time.Time `json:"sent"`    // rule -> job ID -> sent at
+	Recent  map[string][]time.Time          `json:"recent"`  // rule -> send times inside the window
+	Pending map[string][]models.Job         `json:"pending"` // rule -> rate-limited or failed alerts, oldest first
+}
+
+// Notifier evaluates saved searches against newly seen jobs and fires webhooks
+type Notifier struct {
+	hooks     []*webhook
+	statePath string
+	state     state
+	client    *http.Client
+}
+
+// LoadRules reads webhook rules from a JSON file containing an array of rules
+func LoadRules(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read webhook rules: %v", err)
+	}
+
+	var rules []Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook rules: %v", err)
+	}
+	return rules, nil
+}
+
+// New compiles rules against searches and loads the de-duplication state from statePath
+func New(rules []Rule, searches []search.Saved, statePath string) (*Notifier, error) {
+	n := &Notifier{
+		statePath: statePath,
+		state: state{
+			Sent:    make(map[string]map[string]time.Time),
+			Recent:  make(map[string][]time.Time),
+			Pending: make(map[string][]models.Job),
+		},
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+
+	for _, r := range rules {
+		w, err := compile(r, searches)
+		if err != nil {
+			return nil, err
+		}
+		n.hooks = append(n.hooks, w)
+	}
+
+	data, err := os.ReadFile(statePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read notify state: %v", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &n.state); err != nil {
+			return nil, fmt.Errorf("failed to decode notify state: %v", err)
+		}
+		// State files written before pending alerts were kept have no such map
+		if n.state.Pending == nil {
+			n.state.Pending = make(map[string][]models.Job)
+		}
+	}
+	return n, nil
+}
+
+// Notify sends a webhook for every (rule, job) pair that matches and hasn't
+// alerted before. Alerts held back by a rate limit or a failed send are kept
+// and go out first on the next call.
+func (n *Notifier) Notify(jobs []models.Job, now time.Time) Report {
+	var report Report
+	n.pruneSent(now)
+
+	for _, w := range n.hooks {
+		key := w.search.Slug() + " " + w.URL
+		sent := n.state.Sent[key]
+		if sent == nil {
+			sent = make(map[string]time.Time)
+			n.state.Sent[key] = sent
+		}
+		recent := pruneWindow(n.state.Recent[key], w.window, now)
+
+		retries := len(n.state.Pending[key])
+		candidates := append(n.state.Pending[key], w.search.Filter(jobs)...)
+		var pending []models.Job
+		handled := make(map[string]bool) // sent, queued or retried in this call
+
+		for i, j := range candidates {
+			if handled[j.ID] {
+				continue
+			}
+			handled[j.ID] = true
+			if _, dup := sent[j.ID]; dup {
+				if i >= retries {
+					report.Duplicates++
+				}
+				continue
+			}
+			if w.MaxPerWindow > 0 && len(recent) >= w.MaxPerWindow {
+				report.RateLimited++
+				pending = append(pending, j)
+				continue
+			}
+
+			body, err := w.render(j)
+			if err != nil {
+				// The same job renders the same way next run, so retrying can't help
+				fmt.Printf("❌ Webhook for %q can't render %s, dropping it: %v\n", w.search.Name, j.ID, err)
+				report.Dropped++
+				continue
+			}
+			if err := w.send(n.client, body); err != nil {
+				fmt.Printf("❌ Webhook for %q failed on %s: %v\n", w.search.Name, j.ID, err)
+				report.Failed++
+				pending = append(pending, j)
+				continue
+			}
+			sent[j.ID] = now
+			recent = append(recent, now)
+			report.Sent++
+			if i < retries {
+				report.Retried++
+			}
+		}
+
+		if len(pending) > maxPending {
+			pending = pending[len(pending)-maxPending:]
+		}
+		if len(pending) > 0 {
+			n.state.Pending[key] = pending
+		} else {
+			delete(n.state.Pending, key)
+		}
+		n.state.Recent[key] = recent
+	}
+	return report
+}
+
+// Save persists the de-duplication and rate-limit state
+func (n *Notifier) Save() error {
+	data, err := json.MarshalIndent(n.state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode notify state: %v", err)
+	}
+	if dir := filepath.Dir(n.statePath); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create notify dir: %v", err)
+		}
+	}
+	if err := os.WriteFile(n.statePath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write notify state: %v", err)
+	}
+	return nil
+}
+
+// pruneSent forgets alerts sent longer than sentRetention ago, including those
+// of rules that have since been removed
+func (n *Notifier) pruneSent(now time.Time) {
+	for key, sent := range n.state.Sent {
+		for id, at := range sent {
+			if now.Sub(at) > sentRetention {
+				delete(sent, id)
+			}
+		}
+		if len(sent) == 0 {
+			delete(n.state.Sent, key)
+		}
+	}
+}
+
+// pruneWindow drops send times that have fallen out of the rate-limit window
+func pruneWindow(times []time.Time, window time.Duration, now time.Time) []time.Time {
+	if window == 0 {
+		return nil
+	}
+	var kept []time.Time
+	for _, t := range times {
+		if now.Sub(t) < window {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,191 @@
+package notify
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+	"github.com/groot34/job-aggregator/scraper/internal/search"
+)
+
+// endpoint is a webhook receiver that can be switched to failing
+type endpoint struct {
+	mu       sync.Mutex
+	received int
+	failing  bool
+}
+
+func (e *endpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.failing {
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+	e.received++
+}
+
+var searches = []search.Saved{{Name: "Go jobs", Skills: []string{"go"}}}
+
+func goJob(id string) models.Job {
+	return models.Job{ID: id, Title: "Go Engineer", Company: "Acme", Tags: []string{"Go"}}
+}
+
+// reload saves n and loads its state into a fresh notifier, as the next run would
+func reload(t *testing.T, n *Notifier, rules []Rule) *Notifier {
+	t.Helper()
+	if err := n.Save(); err != nil {
+		t.Fatal(err)
+	}
+	next, err := New(rules, searches, n.statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return next
+}
+
+func TestNotifyDedupe(t *testing.T) {
+	recv := &endpoint{}
+	srv := httptest.NewServer(recv)
+	defer srv.Close()
+
+	rules := []Rule{{Search: "go-jobs", URL: srv.URL}}
+	n, err := New(rules, searches, filepath.Join(t.TempDir(), "notify.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+
+	other := models.Job{ID: "py", Title: "Python Engineer", Tags: []string{"Python"}}
+	if r := n.Notify([]models.Job{goJob("a"), goJob("b"), other}, now); r.Sent != 2 {
+		t.Errorf("first run = %+v, want 2 sent", r)
+	}
+	n = reload(t, n, rules)
+	if r := n.Notify([]models.Job{goJob("a"), goJob("c")}, now); r.Sent != 1 || r.Duplicates != 1 {
+		t.Errorf("second run = %+v, want 1 sent and 1 duplicate", r)
+	}
+	if recv.received != 3 {
+		t.Errorf("endpoint received %d alerts, want 3", recv.received)
+	}
+}
+
+func TestNotifyRateLimitRetries(t *testing.T) {
+	recv := &endpoint{}
+	srv := httptest.NewServer(recv)
+	defer srv.Close()
+
+	rules := []Rule{{Search: "go-jobs", URL: srv.URL, MaxPerWindow: 2, Window: "1h"}}
+	n, err := New(rules, searches, filepath.Join(t.TempDir(), "notify.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+
+	r := n.Notify([]models.Job{goJob("a"), goJob("b"), goJob("c"), goJob("d")}, now)
+	if r.Sent != 2 || r.RateLimited != 2 {
+		t.Fatalf("first run = %+v, want 2 sent and 2 rate-limited", r)
+	}
+
+	// Still inside the window: nothing more goes out, nothing is lost
+	n = reload(t, n, rules)
+	if r := n.Notify(nil, now.Add(30*time.Minute)); r.Sent != 0 || r.RateLimited != 2 {
+		t.Errorf("inside window = %+v, want 2 still held back", r)
+	}
+
+	// Once the window has passed the held-back alerts go out before new ones
+	n = reload(t, n, rules)
+	r = n.Notify([]models.Job{goJob("c"), goJob("e")}, now.Add(2*time.Hour))
+	if r.Sent != 2 || r.Retried != 2 || r.RateLimited != 1 || r.Duplicates != 0 {
+		t.Errorf("after window = %+v, want c and d retried and e held back", r)
+	}
+	if pending := n.state.Pending["go-jobs "+srv.URL]; len(pending) != 1 || pending[0].ID != "e" {
+		t.Errorf("pending = %v, want only e", pending)
+	}
+}
+
+func TestNotifyRetriesFailedSends(t *testing.T) {
+	recv := &endpoint{failing: true}
+	srv := httptest.NewServer(recv)
+	defer srv.Close()
+
+	rules := []Rule{{Search: "go-jobs", URL: srv.URL}}
+	n, err := New(rules, searches, filepath.Join(t.TempDir(), "notify.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+
+	if r := n.Notify([]models.Job{goJob("a")}, now); r.Failed != 1 {
+		t.Fatalf("first run = %+v, want 1 failed", r)
+	}
+
+	recv.failing = false
+	n = reload(t, n, rules)
+	if r := n.Notify(nil, now.Add(time.Hour)); r.Sent != 1 || r.Retried != 1 {
+		t.Errorf("retry run = %+v, want the failed alert sent", r)
+	}
+	n = reload(t, n, rules)
+	if r := n.Notify([]models.Job{goJob("a")}, now.Add(2*time.Hour)); r.Sent != 0 || r.Duplicates != 1 {
+		t.Errorf("after retry = %+v, want a treated as a duplicate", r)
+	}
+}
+
+func TestNotifyDropsUnrenderableAlerts(t *testing.T) {
+	recv := &endpoint{}
+	srv := httptest.NewServer(recv)
+	defer srv.Close()
+
+	// Valid template, but Job has no Missing field, so every render fails
+	rules := []Rule{{Search: "go-jobs", URL: srv.URL, Template: `{"x": {{json .Job.Missing}}}`}}
+	n, err := New(rules, searches, filepath.Join(t.TempDir(), "notify.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+
+	if r := n.Notify([]models.Job{goJob("a")}, now); r.Dropped != 1 || r.Failed != 0 {
+		t.Fatalf("first run = %+v, want 1 dropped", r)
+	}
+	n = reload(t, n, rules)
+	if r := n.Notify(nil, now.Add(time.Hour)); r != (Report{}) || len(n.state.Pending) != 0 {
+		t.Errorf("next run = %+v with pending %v, want nothing retried", r, n.state.Pending)
+	}
+	if recv.received != 0 {
+		t.Errorf("endpoint received %d alerts", recv.received)
+	}
+}
+
+func TestNotifyForgetsOldSends(t *testing.T) {
+	recv := &endpoint{}
+	srv := httptest.NewServer(recv)
+	defer srv.Close()
+
+	rules := []Rule{{Search: "go-jobs", URL: srv.URL}}
+	n, err := New(rules, searches, filepath.Join(t.TempDir(), "notify.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	n.Notify([]models.Job{goJob("a")}, now)
+	// A rule that has since been removed from the config
+	n.state.Sent["old-search http://gone"] = map[string]time.Time{"z": now}
+
+	n = reload(t, n, rules)
+	if r := n.Notify([]models.Job{goJob("b")}, now.Add(sentRetention/2)); r.Sent != 1 || len(n.state.Sent) != 2 {
+		t.Errorf("inside retention = %+v, sent state %v; want everything remembered", r, n.state.Sent)
+	}
+
+	n = reload(t, n, rules)
+	n.Notify(nil, now.Add(sentRetention+time.Hour))
+	sent := n.state.Sent["go-jobs "+srv.URL]
+	if _, ok := sent["a"]; ok || len(n.state.Sent) != 1 {
+		t.Errorf("after retention sent state = %v, want a and the removed rule forgotten", n.state.Sent)
+	}
+	if _, ok := sent["b"]; !ok {
+		t.Errorf("b was forgotten before its retention ran out")
+	}
+}
@@ -0,0 +1,145 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+	"github.com/groot34/job-aggregator/scraper/internal/search"
+)
+
+// Rule binds a saved search to a webhook endpoint
+type Rule struct {
+	Search string `json:"search"` // saved search name or slug
+	URL    string `json:"url"`
+	// Preset selects a built-in body: "generic" (default), "slack" or "discord".
+	// Template overrides it with a custom text/template that must render JSON.
+	Preset   string `json:"preset,omitempty"`
+	Template string `json:"template,omitempty"`
+	// At most MaxPerWindow alerts are sent per Window (e.g. "1h"); 0 disables the limit
+	MaxPerWindow int    `json:"maxPerWindow,omitempty"`
+	Window       string `json:"window,omitempty"`
+}
+
+// payloadData is what webhook templates are executed against
+type payloadData struct {
+	Search string
+	Job    models.Job
+}
+
+var presets = map[string]string{
+	"generic": `{"search": {{json .Search}}, "job": {{json .Job}}}`,
+	"slack":   `{"text": {{json (printf "New job for *%s*: <%s|%s> at %s%s" .Search .Job.URL .Job.Title .Job.Company (details .Job))}}}`,
+	"discord": `{"content": {{json (printf "New job for **%s**: [%s](%s) at %s%s" .Search .Job.Title .Job.URL .Job.Company (details .Job))}}}`,
+}
+
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		// Slack link syntax uses <url|text>, so keep angle brackets unescaped
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(v); err != nil {
+			return "", err
+		}
+		return strings.TrimSuffix(buf.String(), "\n"), nil
+	},
+	"join":    strings.Join,
+	"details": details,
+}
+
+// details is a one-line suffix with the location, salary and skills of a job
+func details(j models.Job) string {
+	var parts []string
+	if j.Location != "" {
+		parts = append(parts, j.Location)
+	}
+	if j.Remote {
+		parts = append(parts, "Remote")
+	}
+	if j.Salary != "" {
+		parts = append(parts, j.Salary)
+	}
+	if len(j.Tags) > 0 {
+		parts = append(parts, strings.Join(j.Tags, ", "))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " — " + strings.Join(parts, " · ")
+}
+
+// webhook is a compiled Rule ready to send
+type webhook struct {
+	Rule
+	search search.Saved
+	tmpl   *template.Template
+	window time.Duration
+}
+
+func compile(r Rule, searches []search.Saved) (*webhook, error) {
+	s, ok := search.Find(searches, r.Search)
+	if !ok {
+		return nil, fmt.Errorf("webhook references unknown saved search %q", r.Search)
+	}
+	if r.URL == "" {
+		return nil, fmt.Errorf("webhook for %q has no url", r.Search)
+	}
+
+	body := r.Template
+	if body == "" {
+		preset := r.Preset
+		if preset == "" {
+			preset = "generic"
+		}
+		var ok bool
+		if body, ok = presets[preset]; !ok {
+			return nil, fmt.Errorf("unknown webhook preset %q", preset)
+		}
+	}
+	tmpl, err := template.New(s.Slug()).Funcs(templateFuncs).Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook template for %q: %v", r.Search, err)
+	}
+
+	w := &webhook{Rule: r, search: s, tmpl: tmpl}
+	if r.Window != "" {
+		if w.window, err = time.ParseDuration(r.Window); err != nil {
+			return nil, fmt.Errorf("invalid webhook window %q: %v", r.Window, err)
+		}
+	} else if r.MaxPerWindow > 0 {
+		w.window = time.Hour
+	}
+	return w, nil
+}
+
+// render executes the template and checks the result is valid JSON
+func (w *webhook) render(j models.Job) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := w.tmpl.Execute(&buf, payloadData{Search: w.search.Name, Job: j}); err != nil {
+		return nil, fmt.Errorf("failed to render webhook body: %v", err)
+	}
+	if !json.Valid(buf.Bytes()) {
+		return nil, fmt.Errorf("webhook template for %q did not produce valid JSON", w.search.Name)
+	}
+	return buf.Bytes(), nil
+}
+
+// send posts a rendered body to the webhook
+func (w *webhook) send(client *http.Client, body []byte) error {
+	resp, err := client.Post(w.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status: %d", resp.StatusCode)
+	}
+	return nil
+}
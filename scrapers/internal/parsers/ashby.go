@@ -0,0 +1,150 @@
+package parsers
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/ats"
+	"github.com/groot34/job-aggregator/scraper/internal/fetch"
+	"github.com/groot34/job-aggregator/scraper/internal/health"
+	"github.com/groot34/job-aggregator/scraper/internal/htmltext"
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+)
+
+// AshbyParser reads company job boards through Ashby's public Job Postings API
+type AshbyParser struct {
+	Boards []ats.Board
+}
+
+func init() {
+	Register(Info{
+		Name:      "Ashby",
+		IDPrefix:  "ab",
+		RateLimit: &fetch.Limit{DomainGlob: "*ashbyhq.com", Delay: 500 * time.Millisecond, Parallelism: 2},
+		New:       func() Parser { return &AshbyParser{Boards: atsBoards(ats.Ashby)} },
+	})
+}
+
+func (p *AshbyParser) Name() string {
+	return "Ashby"
+}
+
+// Expectations for the API: every field but salary is structured
+func (p *AshbyParser) Expectations() health.Expectations {
+	return health.Expectations{
+		FillRates: map[string]float64{"title": 1, "company": 1, "url": 1, "location": 0.8, "description": 0.9},
+	}
+}
+
+type ashbyJobBoard struct {
+	Jobs []struct {
+		ID                 string `json:"id"`
+		Title              string `json:"title"`
+		Department         string `json:"department"`
+		Team               string `json:"team"`
+		Location           string `json:"location"`
+		SecondaryLocations []struct {
+			Location string `json:"location"`
+		} `json:"secondaryLocations"`
+		IsRemote         bool   `json:"isRemote"`
+		IsListed         *bool  `json:"isListed"`
+		PublishedAt      string `json:"publishedAt"`
+		JobURL           string `json:"jobUrl"`
+		DescriptionPlain string `json:"descriptionPlain"`
+		DescriptionHTML  string `json:"descriptionHtml"`
+		Compensation     *struct {
+			SalarySummary string `json:"scrapeableCompensationSalarySummary"`
+			TierSummary   string `json:"compensationTierSummary"`
+		} `json:"compensation"`
+	} `json:"jobs"`
+}
+
+func (p *AshbyParser) Parse(arg string) ([]models.Job, error) {
+	return collect(p, arg)
+}
+
+// Parts are the board tokens, so each board can be scraped as its own task
+func (p *AshbyParser) Parts() []string {
+	return boardTokens(p.Boards)
+}
+
+// Stream emits each board's jobs as soon as the board has been fetched. arg
+// names a single board token to scrape instead of every configured board.
+func (p *AshbyParser) Stream(arg string, emit Emit) error {
+	boards := selectBoard(p.Boards, ats.Ashby, arg)
+	if len(boards) == 0 {
+		fmt.Println("⚠️  No Ashby boards configured (see ATS_BOARDS_PATH)")
+		return nil
+	}
+	fmt.Printf("🔌 Fetching jobs from %d Ashby boards...\n", len(boards))
+
+	out := &emitter{emit: emit}
+	client := newJSONClient(p.Name())
+	for _, board := range boards {
+		var resp ashbyJobBoard
+		apiURL := "https://api.ashbyhq.com/posting-api/job-board/" + url.PathEscape(board.Token) + "?includeCompensation=true"
+		if err := client.get(apiURL, &resp); err != nil {
+			fmt.Printf("❌ Ashby board %s: %v\n", board.Token, err)
+			out.fail(board.Token)
+			continue
+		}
+
+		for _, a := range resp.Jobs {
+			if a.IsListed != nil && !*a.IsListed {
+				continue
+			}
+
+			locations := []string{a.Location}
+			for _, l := range a.SecondaryLocations {
+				locations = append(locations, l.Location)
+			}
+			location := strings.Join(locations, " / ")
+
+			description := htmltext.CollapseSpace(a.DescriptionPlain)
+			if description == "" {
+				description = htmltext.Text(a.DescriptionHTML)
+			}
+
+			var salary string
+			if c := a.Compensation; c != nil {
+				salary = c.SalarySummary
+				if salary == "" {
+					salary = c.TierSummary
+				}
+			}
+
+			department := a.Department
+			if department == "" {
+				department = a.Team
+			}
+
+			postedAt := parseATSTime(a.PublishedAt)
+			if postedAt.IsZero() {
+				postedAt = time.Now()
+			}
+
+			if !out.send(models.Job{
+				ID:          "ab-" + a.ID,
+				Title:       strings.TrimSpace(a.Title),
+				Company:     board.Name(),
+				Location:    location,
+				Description: description,
+				URL:         a.JobURL,
+				Source:      "Ashby",
+				Board:       board.Token,
+				PostedAt:    postedAt,
+				ScrapedAt:   time.Now(),
+				Remote:      a.IsRemote || strings.Contains(strings.ToLower(location), "remote"),
+				Salary:      salary,
+				Department:  department,
+			}) {
+				return out.err
+			}
+		}
+	}
+
+	fmt.Printf("✅ Found %d jobs from Ashby\n", out.n)
+	return out.result(p.Name())
+}
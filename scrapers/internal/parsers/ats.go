@@ -0,0 +1,92 @@
+package parsers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/groot34/job-aggregator/scraper/internal/ats"
+	"github.com/groot34/job-aggregator/scraper/internal/discovery"
+	"github.com/groot34/job-aggregator/scraper/internal/fetch"
+)
+
+// atsBoards returns the boards hosted on kind: those configured in
+// ATS_BOARDS_PATH (default ats.json; a missing file just means none) plus
+// those found by company discovery. Configured boards win on duplicates.
+func atsBoards(kind ats.Kind) []ats.Board {
+	var boards []ats.Board
+
+	path := os.Getenv("ATS_BOARDS_PATH")
+	if path == "" {
+		path = "ats.json"
+	}
+	if _, err := os.Stat(path); err == nil {
+		configured, err := ats.LoadBoards(path)
+		if err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+		}
+		boards = append(boards, configured...)
+	}
+
+	if store, err := discovery.Load(discovery.Path()); err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	} else {
+		boards = append(boards, store.Boards()...)
+	}
+
+	var out []ats.Board
+	seen := make(map[string]bool)
+	for _, b := range ats.Filter(boards, kind) {
+		if !seen[b.Key()] {
+			seen[b.Key()] = true
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// boardTokens lists the boards' tokens, the parts an ATS parser splits into
+func boardTokens(boards []ats.Board) []string {
+	tokens := make([]string, 0, len(boards))
+	for _, b := range boards {
+		tokens = append(tokens, b.Token)
+	}
+	return tokens
+}
+
+// selectBoard narrows boards to the one with token arg. An unconfigured token
+// is scraped as a board of kind named after itself; an empty arg keeps them all.
+func selectBoard(boards []ats.Board, kind ats.Kind, arg string) []ats.Board {
+	if arg == "" {
+		return boards
+	}
+	for _, b := range boards {
+		if strings.EqualFold(b.Token, arg) {
+			return []ats.Board{b}
+		}
+	}
+	return []ats.Board{{ATS: kind, Token: arg}}
+}
+
+// jsonClient fetches JSON APIs through the shared fetch layer, so API calls
+// get the same rate limits, caching and fixture recording as HTML pages
+type jsonClient struct {
+	pages *fetch.Getter
+}
+
+func newJSONClient(source string) *jsonClient {
+	return &jsonClient{pages: fetch.NewGetter(source, fetch.WithHeaders(map[string]string{"Accept": "application/json"}))}
+}
+
+// get fetches url and decodes the response into v
+func (jc *jsonClient) get(url string, v interface{}) error {
+	body, err := jc.pages.Get(url)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to decode %s: %v", url, err)
+	}
+	return nil
+}
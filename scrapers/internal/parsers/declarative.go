@@ -0,0 +1,333 @@
+package parsers
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+	"github.com/groot34/job-aggregator/scraper/internal/fetch"
+	"github.com/groot34/job-aggregator/scraper/internal/health"
+	"github.com/groot34/job-aggregator/scraper/internal/jobposting"
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// SiteDefinition describes a simple HTML job board declaratively
+type SiteDefinition struct {
+	Name      string   `yaml:"name"`
+	IDPrefix  string   `yaml:"idPrefix"`
+	StartURLs []string `yaml:"startUrls"`
+	Card      string   `yaml:"card"` // selector matching one job card
+
+	Fields     map[string]FieldSelector `yaml:"fields"`     // title, company, location, description, url, salary, postedAt, id
+	URLPrefix  string                   `yaml:"urlPrefix"`  // prepended to relative job URLs
+	Pagination *Pagination              `yaml:"pagination"` // optional
+	DateFormat string                   `yaml:"dateFormat"` // Go layout for postedAt, e.g. "2006-01-02"
+	Tags       []string                 `yaml:"tags"`       // static tags added to every job
+	Remote     bool                     `yaml:"remote"`     // every job is remote; otherwise inferred from location
+
+	// DetailPages visits every job's own page and fills in missing fields
+	// from its schema.org JobPosting data
+	DetailPages bool `yaml:"detailPages"`
+
+	Expect *SiteExpectations `yaml:"expect"` // optional health expectations
+}
+
+// SiteExpectations mirror health.Expectations with YAML keys
+type SiteExpectations struct {
+	MinJobs   int                `yaml:"minJobs"`
+	FillRates map[string]float64 `yaml:"fillRates"`
+}
+
+// FieldSelector extracts one value from a card: the text of Selector, or
+// its Attr attribute when set. An empty Selector means the card itself.
+type FieldSelector struct {
+	Selector string `yaml:"selector"`
+	Attr     string `yaml:"attr"`
+}
+
+// Pagination follows a "next page" link up to MaxPages pages per start URL
+type Pagination struct {
+	Next     string `yaml:"next"`
+	Attr     string `yaml:"attr"` // defaults to href
+	MaxPages int    `yaml:"maxPages"`
+}
+
+// requiredFields must be configured for every definition
+var requiredFields = []string{"title", "url"}
+
+// Validate reports configuration mistakes before any request is made
+func (d SiteDefinition) Validate() error {
+	if d.Name == "" {
+		return fmt.Errorf("site definition has no name")
+	}
+	if d.IDPrefix == "" {
+		return fmt.Errorf("%s: no idPrefix", d.Name)
+	}
+	if len(d.StartURLs) == 0 {
+		return fmt.Errorf("%s: no startUrls", d.Name)
+	}
+	if d.Card == "" {
+		return fmt.Errorf("%s: no card selector", d.Name)
+	}
+	for _, f := range requiredFields {
+		if _, ok := d.Fields[f]; !ok {
+			return fmt.Errorf("%s: missing required field %q", d.Name, f)
+		}
+	}
+	if d.Pagination != nil && d.Pagination.Next == "" {
+		return fmt.Errorf("%s: pagination needs a next selector", d.Name)
+	}
+	return nil
+}
+
+// LoadSiteDefinitions reads every *.yaml / *.yml file in dir. Two sites may
+// not share an ID prefix, since job IDs and --sources rely on it.
+func LoadSiteDefinitions(dir string) ([]SiteDefinition, error) {
+	var paths []string
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, matches...)
+	}
+	sort.Strings(paths)
+
+	var defs []SiteDefinition
+	prefixes := make(map[string]string) // lowercase ID prefix -> site name
+	for _, path := range paths {
+		def, err := LoadSiteDefinition(path)
+		if err != nil {
+			return nil, err
+		}
+		key := strings.ToLower(def.IDPrefix)
+		if other, dup := prefixes[key]; dup {
+			return nil, fmt.Errorf("%s: ID prefix %q is already used by %s", path, def.IDPrefix, other)
+		}
+		prefixes[key] = def.Name
+		defs = append(defs, def)
+	}
+	return defs, nil
+}
+
+// LoadSiteDefinition reads and validates a single YAML definition. The ID
+// prefix defaults to a slug of the name.
+func LoadSiteDefinition(path string) (SiteDefinition, error) {
+	var def SiteDefinition
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return def, fmt.Errorf("failed to read site definition: %v", err)
+	}
+	if err := yaml.Unmarshal(data, &def); err != nil {
+		return def, fmt.Errorf("failed to decode %s: %v", path, err)
+	}
+	if def.IDPrefix == "" {
+		def.IDPrefix = idSlug(def.Name)
+	}
+	if err := def.Validate(); err != nil {
+		return def, fmt.Errorf("%s: %v", path, err)
+	}
+	return def, nil
+}
+
+// SelectorParser scrapes a board described by a SiteDefinition
+type SelectorParser struct {
+	Def SiteDefinition
+}
+
+func (p *SelectorParser) Name() string {
+	return p.Def.Name
+}
+
+// Expectations come from the definition's expect block, if any
+func (p *SelectorParser) Expectations() health.Expectations {
+	if p.Def.Expect == nil {
+		return health.Expectations{}
+	}
+	return health.Expectations{MinJobs: p.Def.Expect.MinJobs, FillRates: p.Def.Expect.FillRates}
+}
+
+func (p *SelectorParser) Parse(arg string) ([]models.Job, error) {
+	return collect(p, arg)
+}
+
+// Parts are the start URLs; each is scraped with the pages it links to
+func (p *SelectorParser) Parts() []string {
+	return p.Def.StartURLs
+}
+
+// Detail completes jobs from their own pages when the definition asks for it
+func (p *SelectorParser) Detail() func(*models.Job) bool {
+	if !p.Def.DetailPages {
+		return nil
+	}
+	return detailPages(p.Def.Name)
+}
+
+// Stream emits each card as it is parsed. arg names a single start URL to
+// scrape instead of every one in the definition.
+func (p *SelectorParser) Stream(arg string, emit Emit) error {
+	def := p.Def
+	fmt.Printf("🔌 Fetching jobs from %s...\n", def.Name)
+
+	startURLs := def.StartURLs
+	if arg != "" {
+		startURLs = []string{arg}
+	}
+	out := &emitter{emit: emit}
+	seen := make(map[string]bool)
+	pages := 0
+
+	c := fetch.NewCollector(def.Name)
+
+	c.OnHTML(def.Card, func(e *colly.HTMLElement) {
+		if out.stopped() {
+			return
+		}
+		title := p.field(e, "title")
+		link := p.field(e, "url")
+		if title == "" || link == "" {
+			return
+		}
+		link = p.absoluteURL(e, link)
+
+		id := p.field(e, "id")
+		if id == "" {
+			id = shortHash(link)
+		}
+		id = def.IDPrefix + "-" + id
+		if seen[id] {
+			return
+		}
+		seen[id] = true
+
+		location := p.field(e, "location")
+		postedAt := time.Now()
+		if raw := p.field(e, "postedAt"); raw != "" && def.DateFormat != "" {
+			if t, err := time.Parse(def.DateFormat, raw); err == nil {
+				postedAt = t
+			}
+		}
+
+		job := models.Job{
+			ID:          id,
+			Title:       title,
+			Company:     p.field(e, "company"),
+			Location:    location,
+			Description: p.field(e, "description"),
+			URL:         link,
+			Source:      def.Name,
+			PostedAt:    postedAt,
+			ScrapedAt:   time.Now(),
+			Remote:      def.Remote || strings.Contains(strings.ToLower(location), "remote"),
+			Salary:      p.field(e, "salary"),
+			Tags:        append([]string(nil), def.Tags...),
+			Board:       e.Request.Ctx.Get("board"),
+		}
+		out.send(job)
+	})
+
+	c.OnResponse(func(r *colly.Response) {
+		pages++
+	})
+	// A later page failing leaves its start URL as incomplete as the first failing would
+	c.OnError(func(r *colly.Response, err error) {
+		out.fail(r.Ctx.Get("board"))
+	})
+
+	if pg := def.Pagination; pg != nil {
+		attr := pg.Attr
+		if attr == "" {
+			attr = "href"
+		}
+		c.OnHTML(pg.Next, func(e *colly.HTMLElement) {
+			if out.stopped() || (pg.MaxPages > 0 && pages >= pg.MaxPages) {
+				return
+			}
+			if next := e.Attr(attr); next != "" {
+				e.Request.Visit(e.Request.AbsoluteURL(next))
+			}
+		})
+	}
+
+	// Each start URL and the pages it links to are one board
+	for _, u := range startURLs {
+		if out.stopped() {
+			break
+		}
+		ctx := colly.NewContext()
+		ctx.Put("board", u)
+		if err := c.Request("GET", u, nil, ctx, nil); err != nil {
+			fmt.Printf("❌ %s Scrape Error on %s: %v\n", def.Name, u, err)
+			out.fail(u)
+		}
+	}
+
+	fmt.Printf("✅ Found %d jobs from %s\n", out.n, def.Name)
+	return out.result(def.Name)
+}
+
+// detailPages returns a func that completes a listing job from its detail
+// page's JobPosting data. It returns false when the detail page says the
+// posting has expired, so the job should be dropped.
+func detailPages(source string) func(*models.Job) bool {
+	pages := fetch.NewGetter(source)
+	return func(j *models.Job) bool {
+		body, err := pages.Get(j.URL)
+		if err != nil {
+			fmt.Printf("❌ %s Detail Page Error on %s: %v\n", source, j.URL, err)
+			return true
+		}
+		postings, err := jobposting.Extract(string(body), j.URL)
+		if err != nil || len(postings) == 0 {
+			return true
+		}
+		if postings[0].Expired(time.Now()) {
+			return false
+		}
+		postings[0].Fill(j)
+		return true
+	}
+}
+
+// field extracts a configured field from a card, trimmed; unconfigured fields are empty
+func (p *SelectorParser) field(e *colly.HTMLElement, name string) string {
+	fs, ok := p.Def.Fields[name]
+	if !ok {
+		return ""
+	}
+	if fs.Selector == "" {
+		if fs.Attr != "" {
+			return strings.TrimSpace(e.Attr(fs.Attr))
+		}
+		return strings.TrimSpace(e.Text)
+	}
+	if fs.Attr != "" {
+		return strings.TrimSpace(e.ChildAttr(fs.Selector, fs.Attr))
+	}
+	return strings.TrimSpace(e.ChildText(fs.Selector))
+}
+
+// absoluteURL applies urlPrefix to relative links, falling back to the page URL
+func (p *SelectorParser) absoluteURL(e *colly.HTMLElement, link string) string {
+	if strings.HasPrefix(link, "http") {
+		return link
+	}
+	if p.Def.URLPrefix != "" {
+		return strings.TrimSuffix(p.Def.URLPrefix, "/") + "/" + strings.TrimPrefix(link, "/")
+	}
+	return e.Request.AbsoluteURL(link)
+}
+
+// shortHash is a stable ID for boards that expose no job ID of their own
+func shortHash(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return hex.EncodeToString(sum[:6])
+}
@@ -6,26 +6,59 @@ import (
 	"time"
 
 	"github.com/gocolly/colly/v2"
+	"github.com/groot34/job-aggregator/scraper/internal/fetch"
+	"github.com/groot34/job-aggregator/scraper/internal/health"
 	"github.com/groot34/job-aggregator/scraper/internal/models"
 )
 
-type FreshersworldParser struct{}
+type FreshersworldParser struct {
+	DetailPages bool // visit each job's page for the fields its card lacks (DETAIL_PAGES)
+}
+
+func init() {
+	Register(Info{
+		Name:      "Freshersworld",
+		IDPrefix:  "fw",
+		RateLimit: &fetch.Limit{DomainGlob: "*freshersworld.com", Delay: 2 * time.Second, RandomDelay: time.Second, Parallelism: 2},
+		New:       func() Parser { return &FreshersworldParser{DetailPages: DetailPagesEnabled("Freshersworld")} },
+	})
+}
 
 func (p *FreshersworldParser) Name() string {
 	return "Freshersworld"
 }
 
+// Expectations reflect that the card selectors are a best guess: company is often missing
+func (p *FreshersworldParser) Expectations() health.Expectations {
+	return health.Expectations{
+		MinJobs:   5,
+		FillRates: map[string]float64{"title": 1, "url": 1, "location": 0.5, "description": 0.5},
+	}
+}
+
+// Detail fills in the company, salary and whatever else the card lacks from
+// the job's page when DetailPages is set
+func (p *FreshersworldParser) Detail() func(*models.Job) bool {
+	if !p.DetailPages {
+		return nil
+	}
+	return detailPages(p.Name())
+}
+
 func (p *FreshersworldParser) Parse(arg string) ([]models.Job, error) {
+	return collect(p, arg)
+}
+
+// Stream emits each job card as the listing is parsed
+func (p *FreshersworldParser) Stream(arg string, emit Emit) error {
 	fmt.Println("🔌 Fetching jobs from Freshersworld...")
 
 	// Target URL for Freshers
 	targetURL := "https://www.freshersworld.com/jobs"
 
-	var jobs []models.Job
+	out := &emitter{emit: emit}
 
-	c := colly.NewCollector(
-		colly.UserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36"),
-	)
+	c := fetch.NewCollector(p.Name())
 
 	// Select individual job cards
 	// Note: Selectors might change, this is a best-guess based on common structure.
@@ -62,21 +95,17 @@ func (p *FreshersworldParser) Parse(arg string) ([]models.Job, error) {
 			Tags:        []string{"fresher", "india"},
 		}
 
-		jobs = append(jobs, job)
-	})
-
-	c.OnRequest(func(r *colly.Request) {
-		fmt.Println("Visiting", r.URL)
+		out.send(job)
 	})
 
 	// Visit the target
 	err := c.Visit(targetURL)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	fmt.Printf("✅ Found %d jobs from Freshersworld\n", len(jobs))
-	return jobs, nil
+	fmt.Printf("✅ Found %d jobs from Freshersworld\n", out.n)
+	return out.err
 }
 
 func getIDFromURL(url string) string {
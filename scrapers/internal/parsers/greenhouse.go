@@ -0,0 +1,145 @@
+package parsers
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/ats"
+	"github.com/groot34/job-aggregator/scraper/internal/fetch"
+	"github.com/groot34/job-aggregator/scraper/internal/health"
+	"github.com/groot34/job-aggregator/scraper/internal/htmltext"
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+)
+
+// GreenhouseParser reads company job boards through Greenhouse's public Job Board API
+type GreenhouseParser struct {
+	Boards []ats.Board
+}
+
+func init() {
+	Register(Info{
+		Name:      "Greenhouse",
+		IDPrefix:  "gh",
+		RateLimit: &fetch.Limit{DomainGlob: "*greenhouse.io", Delay: 500 * time.Millisecond, Parallelism: 2},
+		New:       func() Parser { return &GreenhouseParser{Boards: atsBoards(ats.Greenhouse)} },
+	})
+}
+
+func (p *GreenhouseParser) Name() string {
+	return "Greenhouse"
+}
+
+// Expectations for the API: every field but salary is structured
+func (p *GreenhouseParser) Expectations() health.Expectations {
+	return health.Expectations{
+		FillRates: map[string]float64{"title": 1, "company": 1, "url": 1, "description": 0.9},
+	}
+}
+
+type greenhouseJobs struct {
+	Jobs []struct {
+		ID             int64  `json:"id"`
+		Title          string `json:"title"`
+		AbsoluteURL    string `json:"absolute_url"`
+		UpdatedAt      string `json:"updated_at"`
+		FirstPublished string `json:"first_published"`
+		Content        string `json:"content"`
+		Location       struct {
+			Name string `json:"name"`
+		} `json:"location"`
+		Departments []struct {
+			Name string `json:"name"`
+		} `json:"departments"`
+		Offices []struct {
+			Name string `json:"name"`
+		} `json:"offices"`
+	} `json:"jobs"`
+}
+
+func (p *GreenhouseParser) Parse(arg string) ([]models.Job, error) {
+	return collect(p, arg)
+}
+
+// Parts are the board tokens, so each board can be scraped as its own task
+func (p *GreenhouseParser) Parts() []string {
+	return boardTokens(p.Boards)
+}
+
+// Stream emits each board's jobs as soon as the board has been fetched. arg
+// names a single board token to scrape instead of every configured board.
+func (p *GreenhouseParser) Stream(arg string, emit Emit) error {
+	boards := selectBoard(p.Boards, ats.Greenhouse, arg)
+	if len(boards) == 0 {
+		fmt.Println("⚠️  No Greenhouse boards configured (see ATS_BOARDS_PATH)")
+		return nil
+	}
+	fmt.Printf("🔌 Fetching jobs from %d Greenhouse boards...\n", len(boards))
+
+	out := &emitter{emit: emit}
+	client := newJSONClient(p.Name())
+	for _, board := range boards {
+		var resp greenhouseJobs
+		apiURL := "https://boards-api.greenhouse.io/v1/boards/" + url.PathEscape(board.Token) + "/jobs?content=true"
+		if err := client.get(apiURL, &resp); err != nil {
+			fmt.Printf("❌ Greenhouse board %s: %v\n", board.Token, err)
+			out.fail(board.Token)
+			continue
+		}
+
+		for _, j := range resp.Jobs {
+			location := strings.TrimSpace(j.Location.Name)
+			if location == "" {
+				var offices []string
+				for _, o := range j.Offices {
+					offices = append(offices, o.Name)
+				}
+				location = strings.Join(offices, " / ")
+			}
+			var departments []string
+			for _, d := range j.Departments {
+				departments = append(departments, d.Name)
+			}
+
+			postedAt := parseATSTime(j.FirstPublished)
+			if postedAt.IsZero() {
+				postedAt = parseATSTime(j.UpdatedAt)
+			}
+			if postedAt.IsZero() {
+				postedAt = time.Now()
+			}
+
+			if !out.send(models.Job{
+				ID:          "gh-" + board.Token + "-" + strconv.FormatInt(j.ID, 10),
+				Title:       strings.TrimSpace(j.Title),
+				Company:     board.Name(),
+				Location:    location,
+				Description: htmltext.Text(j.Content),
+				URL:         j.AbsoluteURL,
+				Source:      "Greenhouse",
+				Board:       board.Token,
+				PostedAt:    postedAt,
+				ScrapedAt:   time.Now(),
+				Remote:      strings.Contains(strings.ToLower(location), "remote"),
+				Department:  strings.Join(departments, ", "),
+			}) {
+				return out.err
+			}
+		}
+	}
+
+	fmt.Printf("✅ Found %d jobs from Greenhouse\n", out.n)
+	return out.result(p.Name())
+}
+
+// parseATSTime reads the ISO 8601 timestamps the ATS APIs use; it is zero when s isn't one
+func parseATSTime(s string) time.Time {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.000-0700", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,299 @@
+package parsers
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/groot34/job-aggregator/scraper/internal/fetch"
+	"github.com/groot34/job-aggregator/scraper/internal/health"
+	"github.com/groot34/job-aggregator/scraper/internal/htmltext"
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+	"github.com/groot34/job-aggregator/scraper/internal/skills"
+)
+
+// HackerNewsParser reads the top-level comments of a monthly
+// "Ask HN: Who is hiring?" thread. Each posting conventionally opens with a
+// "Company | Role | Location | REMOTE | Salary" header line.
+type HackerNewsParser struct {
+	ThreadID string // empty means the latest thread
+}
+
+func init() {
+	Register(Info{
+		Name:      "HackerNews",
+		IDPrefix:  "hn",
+		RateLimit: &fetch.Limit{DomainGlob: "*algolia.com", Delay: 500 * time.Millisecond, Parallelism: 1},
+		New:       func() Parser { return &HackerNewsParser{ThreadID: os.Getenv("HN_THREAD_ID")} },
+	})
+	fetch.RegisterLimit(fetch.Limit{DomainGlob: "*firebaseio.com", Delay: 100 * time.Millisecond, Parallelism: 4})
+}
+
+func (p *HackerNewsParser) Name() string {
+	return "HackerNews"
+}
+
+// Expectations for free-form comments: the header is a convention, not a schema
+func (p *HackerNewsParser) Expectations() health.Expectations {
+	return health.Expectations{
+		MinJobs:   20,
+		FillRates: map[string]float64{"title": 0.8, "company": 1, "location": 0.5},
+	}
+}
+
+// hnItem is an item from the Algolia API, which nests the whole comment tree
+type hnItem struct {
+	ID        int64    `json:"id"`
+	Title     string   `json:"title"`
+	Text      string   `json:"text"`
+	Author    string   `json:"author"`
+	CreatedAt string   `json:"created_at"`
+	Children  []hnItem `json:"children"`
+}
+
+// hnFirebaseItem is an item from the official Firebase API, which lists only child IDs
+type hnFirebaseItem struct {
+	ID      int64   `json:"id"`
+	By      string  `json:"by"`
+	Text    string  `json:"text"`
+	Time    int64   `json:"time"`
+	Kids    []int64 `json:"kids"`
+	Deleted bool    `json:"deleted"`
+	Dead    bool    `json:"dead"`
+}
+
+type hnSearch struct {
+	Hits []struct {
+		ObjectID string `json:"objectID"`
+		Title    string `json:"title"`
+	} `json:"hits"`
+}
+
+const (
+	hnAlgolia  = "https://hn.algolia.com/api/v1/"
+	hnFirebase = "https://hacker-news.firebaseio.com/v0/"
+)
+
+// Parse scrapes the thread given as arg (an item ID or URL), the configured
+// thread, or else the latest one posted by the whoishiring account
+func (p *HackerNewsParser) Parse(arg string) ([]models.Job, error) {
+	return collect(p, arg)
+}
+
+// Stream emits the thread's jobs once its comments have been fetched
+func (p *HackerNewsParser) Stream(arg string, emit Emit) error {
+	client := newJSONClient(p.Name())
+
+	threadID := p.ThreadID
+	if arg != "" {
+		threadID = arg
+	}
+	threadID = hnItemID(threadID)
+	if threadID == "" {
+		var err error
+		if threadID, err = p.latestThread(client); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("🔌 Fetching HN \"Who is hiring\" thread %s...\n", threadID)
+
+	comments, err := p.comments(client, threadID)
+	if err != nil {
+		return err
+	}
+
+	out := &emitter{emit: emit}
+	for _, c := range comments {
+		if job, ok := hnJob(c); ok && !out.send(job) {
+			return out.err
+		}
+	}
+
+	fmt.Printf("✅ Found %d jobs in %d HN comments\n", out.n, len(comments))
+	return nil
+}
+
+// latestThread finds the newest "Who is hiring?" story; the same account also
+// posts "Who wants to be hired?" and freelancer threads
+func (p *HackerNewsParser) latestThread(client *jsonClient) (string, error) {
+	var resp hnSearch
+	if err := client.get(hnAlgolia+"search_by_date?tags=story,author_whoishiring&hitsPerPage=10", &resp); err != nil {
+		return "", fmt.Errorf("failed to find the latest HN hiring thread: %v", err)
+	}
+	for _, hit := range resp.Hits {
+		if strings.Contains(strings.ToLower(hit.Title), "who is hiring") {
+			return hit.ObjectID, nil
+		}
+	}
+	return "", fmt.Errorf("no HN \"Who is hiring\" thread found")
+}
+
+// comments returns the thread's top-level comments. Algolia serves the whole
+// tree in one request; Firebase, one request per comment, is the fallback.
+func (p *HackerNewsParser) comments(client *jsonClient, threadID string) ([]hnItem, error) {
+	var thread hnItem
+	err := client.get(hnAlgolia+"items/"+threadID, &thread)
+	if err == nil {
+		return thread.Children, nil
+	}
+	fmt.Printf("⚠️  HN Algolia API failed (%v), falling back to Firebase\n", err)
+
+	var story hnFirebaseItem
+	if err := client.get(hnFirebase+"item/"+threadID+".json", &story); err != nil {
+		return nil, fmt.Errorf("failed to fetch HN thread %s: %v", threadID, err)
+	}
+	var comments []hnItem
+	for _, kid := range story.Kids {
+		var c hnFirebaseItem
+		if err := client.get(hnFirebase+"item/"+strconv.FormatInt(kid, 10)+".json", &c); err != nil {
+			fmt.Printf("❌ HN comment %d: %v\n", kid, err)
+			continue
+		}
+		if c.Deleted || c.Dead {
+			continue
+		}
+		comments = append(comments, hnItem{
+			ID:        c.ID,
+			Text:      c.Text,
+			Author:    c.By,
+			CreatedAt: time.Unix(c.Time, 0).UTC().Format(time.RFC3339),
+		})
+	}
+	return comments, nil
+}
+
+// hnItemID accepts a bare ID or a news.ycombinator.com/item?id=... URL
+func hnItemID(s string) string {
+	s = strings.TrimSpace(s)
+	if u, err := url.Parse(s); err == nil && u.Query().Get("id") != "" {
+		return u.Query().Get("id")
+	}
+	if _, err := strconv.ParseInt(s, 10, 64); err != nil {
+		return ""
+	}
+	return s
+}
+
+var (
+	hnSalary   = regexp.MustCompile(`[$€£₹]|\d+\s*[kK]\b|(?i)\b(salary|equity|usd|eur|gbp)\b`)
+	hnRemote   = regexp.MustCompile(`(?i)\bremote\b`)
+	hnOnsite   = regexp.MustCompile(`(?i)\b(onsite|on-site|in[- ]office|hybrid)\b`)
+	hnJobType  = regexp.MustCompile(`(?i)^(full[- ]?time|part[- ]?time|contract(or)?|intern(ship)?s?|permanent|ft|pt)([ ,/&]+(full[- ]?time|part[- ]?time|contract(or)?|intern(ship)?s?|permanent))*$`)
+	hnVisa     = regexp.MustCompile(`(?i)\bvisa\b`)
+	hnRole     = regexp.MustCompile(`(?i)\b(engineer(ing)?s?|developers?|scientists?|designers?|managers?|lead|architects?|sre|devops|founding|analysts?|head of|cto|vp|programmers?|researchers?|data|ml|ai|frontend|backend|full[- ]?stack|product|director|administrator|specialist|consultant|technician|recruiter|marketing|sales|operations)\b`)
+	hnURLField = regexp.MustCompile(`^(https?://)?[\w-]+(\.[\w-]+)+(/\S*)?$`)
+)
+
+// hnJob turns a comment into a job; ok is false for comments without a
+// "|"-separated header, which are discussion rather than postings
+func hnJob(c hnItem) (models.Job, bool) {
+	if c.Text == "" || c.Author == "" {
+		return models.Job{}, false
+	}
+	headerHTML := c.Text
+	if i := strings.Index(strings.ToLower(headerHTML), "<p>"); i >= 0 {
+		headerHTML = headerHTML[:i]
+	}
+	header := htmltext.Text(headerHTML)
+	if !strings.Contains(header, "|") {
+		return models.Job{}, false
+	}
+
+	var fields []string
+	for _, f := range strings.Split(header, "|") {
+		if f = strings.TrimSpace(f); f != "" {
+			fields = append(fields, f)
+		}
+	}
+	if len(fields) < 2 {
+		return models.Job{}, false
+	}
+
+	job := models.Job{
+		ID:        "hn-" + strconv.FormatInt(c.ID, 10),
+		Company:   hnCompany(fields[0]),
+		URL:       hnLink(headerHTML),
+		Source:    "HackerNews",
+		PostedAt:  parseATSTime(c.CreatedAt),
+		ScrapedAt: time.Now(),
+	}
+	if job.PostedAt.IsZero() {
+		job.PostedAt = time.Now()
+	}
+	if job.URL == "" {
+		job.URL = "https://news.ycombinator.com/item?id=" + strconv.FormatInt(c.ID, 10)
+	}
+
+	tags := []string{"hn"}
+	var locations, rest []string
+	for _, f := range fields[1:] {
+		lower := strings.ToLower(f)
+		switch {
+		case hnURLField.MatchString(f):
+			// The company's site; the link itself was already taken from the HTML
+		case hnSalary.MatchString(f):
+			job.Salary = f
+		case hnJobType.MatchString(f):
+			tags = append(tags, strings.ReplaceAll(strings.ReplaceAll(lower, " ", "-"), "--", "-"))
+		case hnVisa.MatchString(f):
+			if !strings.Contains(lower, "no ") && !strings.Contains(lower, "not") {
+				tags = append(tags, "visa-sponsorship")
+			}
+		case hnRemote.MatchString(f) || hnOnsite.MatchString(f):
+			if hnRemote.MatchString(f) {
+				job.Remote = true
+			}
+			// "REMOTE" alone says how, not where; "Remote (US)" or "Onsite NYC" says both
+			if place := strings.TrimSpace(hnRemote.ReplaceAllString(hnOnsite.ReplaceAllString(f, ""), "")); len(strings.Trim(place, " ()-,/:;")) > 0 {
+				locations = append(locations, f)
+			} else if hnRemote.MatchString(f) {
+				locations = append(locations, "Remote")
+			}
+		case job.Title == "" && hnRole.MatchString(f):
+			job.Title = f
+		default:
+			rest = append(rest, f)
+		}
+	}
+	// Whatever is left is the role (when no field looked like one) and the location
+	if job.Title == "" && len(rest) > 0 {
+		job.Title, rest = rest[0], rest[1:]
+	}
+	job.Location = strings.Join(append(rest, locations...), " / ")
+
+	job.Description = htmltext.Text(c.Text)
+	found := skills.ExtractSkills(job.Description)
+	sort.Strings(found)
+	job.Tags = append(tags, found...)
+	return job, job.Title != ""
+}
+
+// hnCompany drops the URL or "(YC W21)" style suffixes companies add to their name
+func hnCompany(s string) string {
+	if i := strings.Index(s, "("); i > 0 {
+		s = s[:i]
+	}
+	if i := strings.Index(s, "http"); i > 0 {
+		s = s[:i]
+	}
+	return strings.TrimSpace(strings.Trim(s, " -–—:"))
+}
+
+// hnLink returns the first link in the header, which is usually the company's site or jobs page
+func hnLink(headerHTML string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(headerHTML))
+	if err != nil {
+		return ""
+	}
+	href := doc.Find("a[href]").First().AttrOr("href", "")
+	if !strings.HasPrefix(href, "http") || strings.Contains(href, "news.ycombinator.com") {
+		return ""
+	}
+	return href
+}
@@ -0,0 +1,261 @@
+package parsers
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/feed"
+	"github.com/groot34/job-aggregator/scraper/internal/fetch"
+	"github.com/groot34/job-aggregator/scraper/internal/health"
+	"github.com/groot34/job-aggregator/scraper/internal/htmltext"
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// FeedDefinition describes a job board's RSS, Atom or JSON Feed
+type FeedDefinition struct {
+	Name     string   `yaml:"name"`
+	IDPrefix string   `yaml:"idPrefix"` // defaults to a slug of Name
+	URLs     []string `yaml:"urls"`
+
+	// Fields maps a job field (title, company, location, description, url,
+	// salary, postedAt, id, tags, department) to the entry field holding it,
+	// overriding feedFieldDefaults
+	Fields map[string]string `yaml:"fields"`
+
+	// TitleSeparator splits "Company: Role" style titles, as We Work Remotely uses
+	TitleSeparator string `yaml:"titleSeparator"`
+
+	Company string   `yaml:"company"` // fixed company, for a single company's feed
+	Tags    []string `yaml:"tags"`    // static tags added to every job
+	Remote  bool     `yaml:"remote"`  // every job is remote; otherwise inferred from location and title
+
+	Expect *SiteExpectations `yaml:"expect"` // optional health expectations
+}
+
+// feedFieldDefaults are the entry fields tried, in order, for each job field
+// across RSS, Atom and JSON Feed
+var feedFieldDefaults = map[string][]string{
+	"title":       {"title"},
+	"company":     {"author.name", "authors.name", "author", "creator"},
+	"url":         {"link", "url", "external_url"},
+	"description": {"encoded", "content_html", "content", "description", "summary", "content_text"},
+	"postedAt":    {"pubDate", "published", "date_published", "date", "updated", "date_modified"},
+	"id":          {"guid", "id"},
+	"tags":        {"category", "tags"},
+}
+
+// Validate reports configuration mistakes before any request is made
+func (d FeedDefinition) Validate() error {
+	if d.Name == "" {
+		return fmt.Errorf("feed definition has no name")
+	}
+	if d.IDPrefix == "" {
+		return fmt.Errorf("feed %s has no idPrefix", d.Name)
+	}
+	if len(d.URLs) == 0 {
+		return fmt.Errorf("feed %s has no urls", d.Name)
+	}
+	for field := range d.Fields {
+		switch field {
+		case "title", "company", "location", "description", "url", "salary", "postedAt", "id", "tags", "department":
+		default:
+			return fmt.Errorf("feed %s maps unknown field %q", d.Name, field)
+		}
+	}
+	return nil
+}
+
+// LoadFeedDefinitions reads a YAML list of feed definitions
+func LoadFeedDefinitions(path string) ([]FeedDefinition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feed definitions: %v", err)
+	}
+	var defs []FeedDefinition
+	if err := yaml.Unmarshal(data, &defs); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %v", path, err)
+	}
+	prefixes := make(map[string]string) // lowercase ID prefix -> feed name
+	for i := range defs {
+		if defs[i].IDPrefix == "" {
+			defs[i].IDPrefix = idSlug(defs[i].Name)
+		}
+		if err := defs[i].Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		key := strings.ToLower(defs[i].IDPrefix)
+		if other, dup := prefixes[key]; dup {
+			return nil, fmt.Errorf("%s: ID prefix %q is already used by %s", path, defs[i].IDPrefix, other)
+		}
+		prefixes[key] = defs[i].Name
+	}
+	return defs, nil
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// idSlug turns "We Work Remotely" into "we-work-remotely"
+func idSlug(name string) string {
+	return strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// FeedParser scrapes a board's job feed described by a FeedDefinition
+type FeedParser struct {
+	Def FeedDefinition
+}
+
+func (p *FeedParser) Name() string {
+	return p.Def.Name
+}
+
+// Expectations come from the definition's expect block, if any
+func (p *FeedParser) Expectations() health.Expectations {
+	if p.Def.Expect == nil {
+		return health.Expectations{}
+	}
+	return health.Expectations{MinJobs: p.Def.Expect.MinJobs, FillRates: p.Def.Expect.FillRates}
+}
+
+func (p *FeedParser) Parse(arg string) ([]models.Job, error) {
+	return collect(p, arg)
+}
+
+// Parts are the feed URLs, so each feed can be fetched as its own task
+func (p *FeedParser) Parts() []string {
+	return p.Def.URLs
+}
+
+// Stream emits each feed's entries as soon as the feed has been fetched. arg
+// names a single feed URL to fetch instead of every one in the definition.
+func (p *FeedParser) Stream(arg string, emit Emit) error {
+	def := p.Def
+	fmt.Printf("🔌 Fetching job feed %s...\n", def.Name)
+
+	feedURLs := def.URLs
+	if arg != "" {
+		feedURLs = []string{arg}
+	}
+
+	out := &emitter{emit: emit}
+	seen := make(map[string]bool)
+
+	pages := fetch.NewGetter(def.Name, fetch.WithHeaders(map[string]string{
+		"Accept": "application/rss+xml, application/atom+xml, application/feed+json, application/xml;q=0.9, */*;q=0.8",
+	}))
+	for _, feedURL := range feedURLs {
+		if out.stopped() {
+			break
+		}
+		body, err := pages.Get(feedURL)
+		if err != nil {
+			fmt.Printf("❌ %s Scrape Error on %s: %v\n", def.Name, feedURL, err)
+			out.fail(feedURL)
+			continue
+		}
+		entries, format, err := feed.Read(body)
+		if err != nil {
+			fmt.Printf("❌ %s feed %s: %v\n", def.Name, feedURL, err)
+			out.fail(feedURL)
+			continue
+		}
+		fmt.Printf("📰 [%s] %d %s entries from %s\n", def.Name, len(entries), format, feedURL)
+		for _, e := range entries {
+			job, ok := p.job(e)
+			if !ok || seen[job.ID] {
+				continue
+			}
+			seen[job.ID] = true
+			job.Board = feedURL
+			if !out.send(job) {
+				break
+			}
+		}
+	}
+
+	fmt.Printf("✅ Found %d jobs from %s\n", out.n, def.Name)
+	return out.result(def.Name)
+}
+
+// job maps one feed entry to a job; ok is false when it has no title or link
+func (p *FeedParser) job(e feed.Entry) (models.Job, bool) {
+	def := p.Def
+	title := htmltext.Text(e.First(p.keys("title")...))
+	link := e.First(p.keys("url")...)
+	if title == "" || link == "" {
+		return models.Job{}, false
+	}
+
+	// A mapped field beats the fixed company, which beats the title prefix,
+	// which beats the author fields that usually but not always name the company
+	company := def.Company
+	if k, ok := def.Fields["company"]; ok {
+		if mapped := htmltext.Text(e.First(k)); mapped != "" {
+			company = mapped
+		}
+	}
+	if def.TitleSeparator != "" {
+		if before, after, found := strings.Cut(title, def.TitleSeparator); found {
+			if company == "" {
+				company = strings.TrimSpace(before)
+			}
+			title = strings.TrimSpace(after)
+		}
+	}
+	if company == "" {
+		company = htmltext.Text(e.First(feedFieldDefaults["company"]...))
+	}
+
+	id := e.First(p.keys("id")...)
+	if id == "" {
+		id = link
+	}
+
+	postedAt := feed.ParseDate(e.First(p.keys("postedAt")...))
+	if postedAt.IsZero() {
+		postedAt = time.Now()
+	}
+
+	location := htmltext.Text(e.First(p.keys("location")...))
+	remote := def.Remote ||
+		strings.Contains(strings.ToLower(location), "remote") ||
+		strings.Contains(strings.ToLower(title), "remote")
+
+	tags := append([]string(nil), def.Tags...)
+	for _, t := range e.All(p.keys("tags")...) {
+		// Some feeds put every tag in one comma-separated value
+		for _, t := range strings.Split(t, ",") {
+			if t = htmltext.Text(t); t != "" {
+				tags = append(tags, t)
+			}
+		}
+	}
+
+	return models.Job{
+		ID:          def.IDPrefix + "-" + shortHash(id),
+		Title:       title,
+		Company:     company,
+		Location:    location,
+		Description: htmltext.Text(e.First(p.keys("description")...)),
+		URL:         link,
+		Source:      def.Name,
+		PostedAt:    postedAt,
+		ScrapedAt:   time.Now(),
+		Remote:      remote,
+		Salary:      htmltext.Text(e.First(p.keys("salary")...)),
+		Tags:        tags,
+		Department:  htmltext.Text(e.First(p.keys("department")...)),
+	}, true
+}
+
+// keys returns the entry fields to read a job field from: the configured
+// mapping when there is one, the defaults otherwise
+func (p *FeedParser) keys(field string) []string {
+	if k, ok := p.Def.Fields[field]; ok {
+		return []string{k}
+	}
+	return feedFieldDefaults[field]
+}
@@ -0,0 +1,146 @@
+package parsers
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/discovery"
+	"github.com/groot34/job-aggregator/scraper/internal/fetch"
+	"github.com/groot34/job-aggregator/scraper/internal/jobposting"
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+)
+
+// JobPostingParser reads schema.org JobPosting data (JSON-LD or microdata)
+// from arbitrary pages, such as company career pages that no other parser covers
+type JobPostingParser struct {
+	URLs []string
+}
+
+func init() {
+	Register(Info{
+		Name:     "JobPosting",
+		IDPrefix: "jp",
+		New: func() Parser {
+			return &JobPostingParser{URLs: jobPostingURLs()}
+		},
+	})
+}
+
+// jobPostingURLs returns JOBPOSTING_URLS plus the careers pages found by company discovery
+func jobPostingURLs() []string {
+	urls := splitList(os.Getenv("JOBPOSTING_URLS"))
+	store, err := discovery.Load(discovery.Path())
+	if err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+		return urls
+	}
+	seen := make(map[string]bool)
+	for _, u := range urls {
+		seen[u] = true
+	}
+	for _, u := range store.CareersPages() {
+		if !seen[u] {
+			seen[u] = true
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
+func (p *JobPostingParser) Name() string {
+	return "JobPosting"
+}
+
+// Parts are the configured pages, so each can be scraped as its own task
+func (p *JobPostingParser) Parts() []string {
+	return p.URLs
+}
+
+// Parse scrapes arg when given, otherwise every configured URL
+func (p *JobPostingParser) Parse(arg string) ([]models.Job, error) {
+	return collect(p, arg)
+}
+
+// Stream emits each page's postings as soon as the page has been fetched
+func (p *JobPostingParser) Stream(arg string, emit Emit) error {
+	urls := p.URLs
+	if arg != "" {
+		urls = []string{arg}
+	}
+	if len(urls) == 0 {
+		fmt.Println("⚠️  No JobPosting URLs configured (set JOBPOSTING_URLS)")
+		return nil
+	}
+	fmt.Printf("🔌 Fetching schema.org job postings from %d pages...\n", len(urls))
+
+	out := &emitter{emit: emit}
+	seen := make(map[string]bool)
+	expired := 0
+
+	pages := fetch.NewGetter(p.Name())
+	for _, pageURL := range urls {
+		if out.stopped() {
+			break
+		}
+		body, err := pages.Get(pageURL)
+		if err != nil {
+			fmt.Printf("❌ JobPosting Scrape Error on %s: %v\n", pageURL, err)
+			out.fail(pageURL)
+			continue
+		}
+		postings, err := jobposting.Extract(string(body), pageURL)
+		if err != nil {
+			fmt.Printf("❌ JobPosting %s: %v\n", pageURL, err)
+			out.fail(pageURL)
+			continue
+		}
+		if len(postings) == 0 {
+			fmt.Printf("⚠️  No JobPosting data on %s\n", pageURL)
+			continue
+		}
+		for _, posting := range postings {
+			if posting.Expired(time.Now()) {
+				expired++
+				continue
+			}
+			// Identifiers are only unique per site, so the host is part of the ID
+			job := posting.Job(p.Name(), "jp-"+hostOf(pageURL))
+			job.Board = pageURL
+			if !seen[job.ID] {
+				seen[job.ID] = true
+				if !out.send(job) {
+					break
+				}
+			}
+		}
+	}
+
+	if expired > 0 {
+		fmt.Printf("⚠️  Skipped %d JobPosting entries past their validThrough date\n", expired)
+	}
+	fmt.Printf("✅ Found %d jobs from JobPosting pages\n", out.n)
+	return out.result(p.Name())
+}
+
+// hostOf returns the URL's host without a leading "www."
+func hostOf(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(u.Hostname(), "www.")
+}
+
+// splitList splits a comma-separated setting, dropping blanks
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
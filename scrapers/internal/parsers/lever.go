@@ -0,0 +1,157 @@
+package parsers
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/ats"
+	"github.com/groot34/job-aggregator/scraper/internal/fetch"
+	"github.com/groot34/job-aggregator/scraper/internal/health"
+	"github.com/groot34/job-aggregator/scraper/internal/htmltext"
+	"github.com/groot34/job-aggregator/scraper/internal/jobposting"
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+)
+
+// LeverParser reads company job boards through Lever's public Postings API
+type LeverParser struct {
+	Boards []ats.Board
+}
+
+func init() {
+	Register(Info{
+		Name:      "Lever",
+		IDPrefix:  "lv",
+		RateLimit: &fetch.Limit{DomainGlob: "*lever.co", Delay: 500 * time.Millisecond, Parallelism: 2},
+		New:       func() Parser { return &LeverParser{Boards: atsBoards(ats.Lever)} },
+	})
+}
+
+func (p *LeverParser) Name() string {
+	return "Lever"
+}
+
+// Expectations for the API: every field but salary is structured
+func (p *LeverParser) Expectations() health.Expectations {
+	return health.Expectations{
+		FillRates: map[string]float64{"title": 1, "company": 1, "url": 1, "location": 0.8, "description": 0.9},
+	}
+}
+
+type leverPosting struct {
+	ID               string `json:"id"`
+	Text             string `json:"text"`
+	HostedURL        string `json:"hostedUrl"`
+	CreatedAt        int64  `json:"createdAt"` // milliseconds since the epoch
+	DescriptionPlain string `json:"descriptionPlain"`
+	AdditionalPlain  string `json:"additionalPlain"`
+	WorkplaceType    string `json:"workplaceType"`
+	Categories       struct {
+		Location     string   `json:"location"`
+		AllLocations []string `json:"allLocations"`
+		Team         string   `json:"team"`
+		Department   string   `json:"department"`
+	} `json:"categories"`
+	Lists []struct {
+		Text    string `json:"text"`
+		Content string `json:"content"`
+	} `json:"lists"`
+	SalaryRange *struct {
+		Min      float64 `json:"min"`
+		Max      float64 `json:"max"`
+		Currency string  `json:"currency"`
+		Interval string  `json:"interval"`
+	} `json:"salaryRange"`
+}
+
+// leverIntervals maps Lever's pay intervals to the units FormatSalary prints
+var leverIntervals = map[string]string{
+	"per-year-salary":  "year",
+	"per-month-salary": "month",
+	"per-week-salary":  "week",
+	"per-day-wage":     "day",
+	"per-hour-wage":    "hour",
+}
+
+func (p *LeverParser) Parse(arg string) ([]models.Job, error) {
+	return collect(p, arg)
+}
+
+// Parts are the board tokens, so each board can be scraped as its own task
+func (p *LeverParser) Parts() []string {
+	return boardTokens(p.Boards)
+}
+
+// Stream emits each board's jobs as soon as the board has been fetched. arg
+// names a single board token to scrape instead of every configured board.
+func (p *LeverParser) Stream(arg string, emit Emit) error {
+	boards := selectBoard(p.Boards, ats.Lever, arg)
+	if len(boards) == 0 {
+		fmt.Println("⚠️  No Lever boards configured (see ATS_BOARDS_PATH)")
+		return nil
+	}
+	fmt.Printf("🔌 Fetching jobs from %d Lever boards...\n", len(boards))
+
+	out := &emitter{emit: emit}
+	client := newJSONClient(p.Name())
+	for _, board := range boards {
+		var postings []leverPosting
+		apiURL := "https://api.lever.co/v0/postings/" + url.PathEscape(board.Token) + "?mode=json"
+		if err := client.get(apiURL, &postings); err != nil {
+			fmt.Printf("❌ Lever board %s: %v\n", board.Token, err)
+			out.fail(board.Token)
+			continue
+		}
+
+		for _, l := range postings {
+			location := l.Categories.Location
+			if len(l.Categories.AllLocations) > 0 {
+				location = strings.Join(l.Categories.AllLocations, " / ")
+			}
+
+			// The plain description omits the requirement lists, which Lever keeps separately
+			parts := []string{l.DescriptionPlain}
+			for _, list := range l.Lists {
+				parts = append(parts, list.Text+": "+htmltext.Text(list.Content))
+			}
+			parts = append(parts, l.AdditionalPlain)
+
+			var salary string
+			if r := l.SalaryRange; r != nil {
+				salary = jobposting.FormatSalary(r.Currency, r.Min, r.Max, leverIntervals[r.Interval])
+			}
+
+			department := l.Categories.Department
+			if department == "" {
+				department = l.Categories.Team
+			}
+
+			postedAt := time.Now()
+			if l.CreatedAt > 0 {
+				postedAt = time.UnixMilli(l.CreatedAt).UTC()
+			}
+
+			if !out.send(models.Job{
+				ID:          "lv-" + l.ID,
+				Title:       strings.TrimSpace(l.Text),
+				Company:     board.Name(),
+				Location:    location,
+				Description: htmltext.CollapseSpace(strings.Join(parts, " ")),
+				URL:         l.HostedURL,
+				Source:      "Lever",
+				Board:       board.Token,
+				PostedAt:    postedAt,
+				ScrapedAt:   time.Now(),
+				Remote:      l.WorkplaceType == "remote" || strings.Contains(strings.ToLower(location), "remote"),
+				Salary:      salary,
+				Department:  department,
+			}) {
+				return out.err
+			}
+		}
+	}
+
+	fmt.Printf("✅ Found %d jobs from Lever\n", out.n)
+	return out.result(p.Name())
+}
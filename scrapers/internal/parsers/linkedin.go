@@ -6,28 +6,75 @@ import (
 	"time"
 
 	"github.com/gocolly/colly/v2"
+	"github.com/groot34/job-aggregator/scraper/internal/fetch"
+	"github.com/groot34/job-aggregator/scraper/internal/health"
 	"github.com/groot34/job-aggregator/scraper/internal/models"
 )
 
-type LinkedInParser struct{}
+type LinkedInParser struct {
+	DetailPages bool // visit each job's page for its full description (DETAIL_PAGES)
+}
+
+// linkedInPlaceholder stands in for the description the search cards don't carry
+const linkedInPlaceholder = "Click to apply on LinkedIn to view full description."
+
+func init() {
+	Register(Info{
+		Name:      "LinkedIn",
+		IDPrefix:  "li",
+		RateLimit: &fetch.Limit{DomainGlob: "*linkedin.com", Delay: 5 * time.Second, RandomDelay: 3 * time.Second, Parallelism: 1},
+		New:       func() Parser { return &LinkedInParser{DetailPages: DetailPagesEnabled("LinkedIn")} },
+	})
+}
 
 func (p *LinkedInParser) Name() string {
 	return "LinkedIn"
 }
 
+// Expectations for the public search page, which lists 25 cards per page
+func (p *LinkedInParser) Expectations() health.Expectations {
+	return health.Expectations{
+		MinJobs:   10,
+		FillRates: map[string]float64{"title": 1, "url": 1, "company": 0.9, "location": 0.9},
+	}
+}
+
+// Detail replaces the placeholder description with the one on the job's page
+// when DetailPages is set. The page often sits behind an auth wall, so a job
+// whose page has no JobPosting data keeps the placeholder.
+func (p *LinkedInParser) Detail() func(*models.Job) bool {
+	if !p.DetailPages {
+		return nil
+	}
+	fill := detailPages(p.Name())
+	return func(j *models.Job) bool {
+		if j.Description == linkedInPlaceholder {
+			j.Description = ""
+		}
+		ok := fill(j)
+		if j.Description == "" {
+			j.Description = linkedInPlaceholder
+		}
+		return ok
+	}
+}
+
 func (p *LinkedInParser) Parse(arg string) ([]models.Job, error) {
+	return collect(p, arg)
+}
+
+// Stream emits each job card as the search page is parsed
+func (p *LinkedInParser) Stream(arg string, emit Emit) error {
 	fmt.Println("🔌 Fetching jobs from LinkedIn...")
 
 	// URL: Public job search for Software Engineers in India
 	// We can parameterize this later
 	targetURL := "https://www.linkedin.com/jobs/search?keywords=software%20engineer&location=India&geoId=102713980&trk=public_jobs_jobs-search-bar_search-submit&position=1&pageNum=0"
 
-	var jobs []models.Job
+	out := &emitter{emit: emit}
 
-	c := colly.NewCollector(
-		// LinkedIn is sensitive to User-Agents. Use a standard one.
-		colly.UserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36"),
-	)
+	// LinkedIn is sensitive to User-Agents; the fetcher rotates through standard browser ones
+	c := fetch.NewCollector(p.Name())
 
 	// LinkedIn public job cards usually define this stricture
 	c.OnHTML("ul.jobs-search__results-list li", func(e *colly.HTMLElement) {
@@ -59,9 +106,8 @@ func (p *LinkedInParser) Parse(arg string) ([]models.Job, error) {
 			Source:    "LinkedIn",
 			PostedAt:  postedAt,
 			ScrapedAt: time.Now(),
-			// Description is not on the card, would need to visit details page
-			// But visiting details page triggers auth wall often.
-			Description: "Click to apply on LinkedIn to view full description.",
+			// Description is not on the card; Detail fetches it when DETAIL_PAGES opts in
+			Description: linkedInPlaceholder,
 			Remote:      strings.Contains(strings.ToLower(location), "remote"),
 		}
 
@@ -70,19 +116,15 @@ func (p *LinkedInParser) Parse(arg string) ([]models.Job, error) {
 			return
 		}
 
-		jobs = append(jobs, job)
-	})
-
-	c.OnRequest(func(r *colly.Request) {
-		fmt.Println("Visiting", r.URL)
+		out.send(job)
 	})
 
 	err := c.Visit(targetURL)
 	if err != nil {
 		fmt.Printf("❌ LinkedIn Scrape Error: %v\n", err)
-		return nil, nil // Return empty, don't crash the whole run
+		return out.err // Don't crash the whole run
 	}
 
-	fmt.Printf("✅ Found %d jobs from LinkedIn\n", len(jobs))
-	return jobs, nil
+	fmt.Printf("✅ Found %d jobs from LinkedIn\n", out.n)
+	return out.err
 }
@@ -1,6 +1,10 @@
 package parsers
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/groot34/job-aggregator/scraper/internal/health"
 	"github.com/groot34/job-aggregator/scraper/internal/models"
 )
 
@@ -9,3 +13,129 @@ type Parser interface {
 	Parse(url string) ([]models.Job, error)
 	Name() string
 }
+
+// Emit receives a job as soon as a parser has it. Returning an error stops
+// the parser, which passes the error back from Stream.
+type Emit func(models.Job) error
+
+// Streamer is implemented by parsers that hand over each job as they find it,
+// so downstream stages start before the scrape ends and a failure midway keeps
+// the jobs already emitted. Emit is never called concurrently by one Stream.
+type Streamer interface {
+	Stream(url string, emit Emit) error
+}
+
+// Splitter is implemented by parsers that scrape several boards or pages.
+// Passing one of Parts to Stream scrapes just that one, so each can run as its
+// own scheduler task.
+type Splitter interface {
+	Parts() []string
+}
+
+// Detailer is implemented by parsers that can complete a listing job from the
+// job's own page. Detail returns nil when the parser isn't set up to; otherwise
+// its func fills in j and returns false when the page says the job has expired.
+// The func may be called from several goroutines at once.
+//
+// Stream emits listing jobs as they are; Parse completes them first.
+type Detailer interface {
+	Detail() func(j *models.Job) bool
+}
+
+// Stream runs p, emitting jobs as they are found when p is a Streamer and all
+// at once when Parse returns otherwise
+func Stream(p Parser, url string, emit Emit) error {
+	if s, ok := p.(Streamer); ok {
+		return s.Stream(url, emit)
+	}
+	jobs, err := p.Parse(url)
+	for _, j := range jobs {
+		if err := emit(j); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// collect implements Parse for a Streamer by gathering everything it emits,
+// completed from detail pages when it is a Detailer that fetches them
+func collect(s Streamer, url string) ([]models.Job, error) {
+	var fill func(*models.Job) bool
+	if d, ok := s.(Detailer); ok {
+		fill = d.Detail()
+	}
+	var jobs []models.Job
+	err := s.Stream(url, func(j models.Job) error {
+		if fill == nil || fill(&j) {
+			jobs = append(jobs, j)
+		}
+		return nil
+	})
+	return jobs, err
+}
+
+// BoardsError is returned by parsers that scrape several boards or pages when
+// some of them couldn't be fetched. Every other board's jobs were emitted in
+// full, so only the jobs on the failed boards are in doubt.
+type BoardsError struct {
+	Source string
+	Failed []string
+}
+
+func (e *BoardsError) Error() string {
+	return fmt.Sprintf("%s: %d boards failed: %s", e.Source, len(e.Failed), strings.Join(e.Failed, ", "))
+}
+
+// emitter counts what a parser emitted and keeps the first emit error, for
+// jobs found inside callbacks that have no way to return one
+type emitter struct {
+	emit   Emit
+	n      int
+	err    error
+	failed []string // boards or pages that couldn't be fetched
+}
+
+// fail records a board or page whose jobs couldn't be fetched
+func (e *emitter) fail(board string) {
+	for _, b := range e.failed {
+		if b == board {
+			return
+		}
+	}
+	e.failed = append(e.failed, board)
+}
+
+// result is what Stream returns: the emit error if there was one, otherwise
+// a BoardsError for the failed boards, if any
+func (e *emitter) result(source string) error {
+	if e.err != nil {
+		return e.err
+	}
+	if len(e.failed) > 0 {
+		return &BoardsError{Source: source, Failed: e.failed}
+	}
+	return nil
+}
+
+// send emits j unless an earlier emit failed; it reports whether to keep going
+func (e *emitter) send(j models.Job) bool {
+	if e.err != nil {
+		return false
+	}
+	if e.err = e.emit(j); e.err != nil {
+		return false
+	}
+	e.n++
+	return true
+}
+
+// stopped reports whether an emit has failed, so no more pages should be fetched
+func (e *emitter) stopped() bool {
+	return e.err != nil
+}
+
+// HealthChecker is implemented by parsers that declare what a healthy scrape
+// looks like, so selector drift shows up as an anomaly instead of zero jobs
+type HealthChecker interface {
+	Expectations() health.Expectations
+}
@@ -0,0 +1,370 @@
+package parsers
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/ats"
+	"github.com/groot34/job-aggregator/scraper/internal/browser"
+	"github.com/groot34/job-aggregator/scraper/internal/fixtures"
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files from the current parser output")
+
+// replay serves testdata/<name> through an httptest server for the duration of the test
+func replay(t *testing.T, name string) {
+	t.Helper()
+
+	set, err := fixtures.Load(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := set.Start()
+	fixtures.SetReplay(srv.URL)
+	t.Cleanup(func() {
+		fixtures.SetReplay("")
+		srv.Close()
+	})
+}
+
+// checkGolden compares jobs against testdata/<name>/golden.json.
+// Timestamps that parsers fill with time.Now() are zeroed so the files stay stable.
+func checkGolden(t *testing.T, name string, jobs []models.Job, start time.Time) {
+	t.Helper()
+
+	for i := range jobs {
+		jobs[i].ScrapedAt = time.Time{}
+		if !jobs[i].PostedAt.Before(start) {
+			jobs[i].PostedAt = time.Time{}
+		}
+	}
+	got, err := json.MarshalIndent(jobs, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = append(got, '\n')
+
+	path := filepath.Join("testdata", name, "golden.json")
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("missing golden file (run with -update): %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("%s output differs from %s\ngot:\n%s\nwant:\n%s", name, path, got, want)
+	}
+}
+
+func TestFreshersworldParser(t *testing.T) {
+	replay(t, "freshersworld")
+	start := time.Now()
+
+	jobs, err := (&FreshersworldParser{}).Parse("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "freshersworld", jobs, start)
+}
+
+func TestLinkedInParser(t *testing.T) {
+	replay(t, "linkedin")
+	start := time.Now()
+
+	jobs, err := (&LinkedInParser{}).Parse("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "linkedin", jobs, start)
+}
+
+// With detail pages on, LinkedIn's placeholder gives way to the posting's own
+// description; cards whose page can't be fetched keep it
+func TestLinkedInParserDetailPages(t *testing.T) {
+	replay(t, "linkedin-detail")
+	start := time.Now()
+
+	jobs, err := (&LinkedInParser{DetailPages: true}).Parse("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "linkedin-detail", jobs, start)
+}
+
+func TestWellfoundParser(t *testing.T) {
+	replay(t, "wellfound")
+	start := time.Now()
+
+	jobs, err := (&WellfoundParser{}).Parse("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "wellfound", jobs, start)
+}
+
+func TestSelectorParser(t *testing.T) {
+	def, err := LoadSiteDefinition(filepath.Join("testdata", "declarative", "site.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	replay(t, "declarative")
+	start := time.Now()
+
+	jobs, err := (&SelectorParser{Def: def}).Parse("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "declarative", jobs, start)
+}
+
+func TestStreamStopsOnEmitError(t *testing.T) {
+	def, err := LoadSiteDefinition(filepath.Join("testdata", "declarative", "site.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	replay(t, "declarative")
+
+	// An emit error ends the scrape, including the pages still to be visited
+	stop := errors.New("stop")
+	var got []models.Job
+	err = Stream(&SelectorParser{Def: def}, "", func(j models.Job) error {
+		got = append(got, j)
+		return stop
+	})
+	if err != stop {
+		t.Fatalf("err = %v, want the emit error", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("emitted %d jobs after the emit error, want 1", len(got))
+	}
+}
+
+func TestJobPostingParser(t *testing.T) {
+	replay(t, "jobposting")
+	start := time.Now()
+
+	p := &JobPostingParser{URLs: []string{"https://careers.globex.com/jobs", "https://jobs.example.com/openings/201"}}
+	jobs, err := p.Parse("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "jobposting", jobs, start)
+}
+
+func TestGreenhouseParser(t *testing.T) {
+	replay(t, "greenhouse")
+	start := time.Now()
+
+	p := &GreenhouseParser{Boards: []ats.Board{{ATS: ats.Greenhouse, Token: "acme", Company: "Acme"}}}
+	jobs, err := p.Parse("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "greenhouse", jobs, start)
+}
+
+func TestGreenhouseParserReportsFailedBoards(t *testing.T) {
+	replay(t, "greenhouse")
+
+	p := &GreenhouseParser{Boards: []ats.Board{
+		{ATS: ats.Greenhouse, Token: "acme", Company: "Acme"},
+		{ATS: ats.Greenhouse, Token: "gone", Company: "Gone"},
+	}}
+	jobs, err := p.Parse("")
+	var partial *BoardsError
+	if !errors.As(err, &partial) || !reflect.DeepEqual(partial.Failed, []string{"gone"}) {
+		t.Fatalf("err = %v, want a BoardsError for gone", err)
+	}
+	if len(jobs) == 0 {
+		t.Fatal("the working board's jobs were not emitted")
+	}
+	for _, j := range jobs {
+		if j.Board != "acme" {
+			t.Errorf("%s board = %q, want acme", j.ID, j.Board)
+		}
+	}
+}
+
+func TestLeverParser(t *testing.T) {
+	replay(t, "lever")
+	start := time.Now()
+
+	p := &LeverParser{Boards: []ats.Board{{ATS: ats.Lever, Token: "globex"}}}
+	jobs, err := p.Parse("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "lever", jobs, start)
+}
+
+func TestAshbyParser(t *testing.T) {
+	replay(t, "ashby")
+	start := time.Now()
+
+	p := &AshbyParser{Boards: []ats.Board{{ATS: ats.Ashby, Token: "initech", Company: "Initech"}}}
+	jobs, err := p.Parse("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "ashby", jobs, start)
+}
+
+func TestHackerNewsParser(t *testing.T) {
+	replay(t, "hackernews")
+	start := time.Now()
+
+	// No thread ID, so the latest "Who is hiring" thread is looked up first
+	p := &HackerNewsParser{}
+	jobs, err := p.Parse("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "hackernews", jobs, start)
+}
+
+func TestFeedParser(t *testing.T) {
+	replay(t, "jobfeed")
+	start := time.Now()
+
+	// One definition over all three formats, relying on the default mappings
+	p := &FeedParser{Def: FeedDefinition{
+		Name:     "ExampleFeeds",
+		IDPrefix: "ef",
+		URLs: []string{
+			"https://weworkremotely.example.com/remote-programming-jobs.rss",
+			"https://acme.example.com/careers/feed.atom",
+			"https://jobs.example.org/feed.json",
+		},
+		Fields:         map[string]string{"location": "region"},
+		TitleSeparator: ": ",
+		Tags:           []string{"feed"},
+	}}
+	jobs, err := p.Parse("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "jobfeed", jobs, start)
+}
+
+func TestYCombinatorEmbeddedProps(t *testing.T) {
+	html, err := os.ReadFile(filepath.Join("testdata", "ycombinator-props", "page.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+
+	jobs, ok := ycJobsFromProps(string(html))
+	if !ok {
+		t.Fatal("no jobs found in embedded props")
+	}
+	checkGolden(t, "ycombinator-props", jobs, start)
+
+	if _, ok := ycJobsFromProps("<html><body><a href=\"/companies/x\">x</a></body></html>"); ok {
+		t.Error("page without props should fall back to DOM heuristics")
+	}
+}
+
+func TestYCombinatorMerge(t *testing.T) {
+	html, err := os.ReadFile(filepath.Join("testdata", "ycombinator-props", "page.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	props, _ := ycJobsFromProps(string(html))
+
+	// The first link duplicates a props job; the second was loaded by scrolling
+	dom := ycJobsFromDOM([]map[string]interface{}{
+		{"title": "Backend Engineer", "company": "Acme Robotics (W21)", "url": "https://www.ycombinator.com/companies/acme-robotics/jobs/Xb12Cd3-backend-engineer"},
+		{"title": "ML Engineer", "company": "Tensorly (W24)", "url": "https://www.ycombinator.com/companies/tensorly/jobs/Zz9-ml-engineer", "location": "Remote"},
+	})
+	jobs := ycMerge(props, dom)
+
+	if len(jobs) != len(props)+1 {
+		t.Fatalf("merged %d jobs, want %d", len(jobs), len(props)+1)
+	}
+	if jobs[0].Description == "" {
+		t.Error("job found in both lost its props fields")
+	}
+	if last := jobs[len(jobs)-1]; last.ID != "yc-tensorly-jobs-Zz9-ml-engineer" || !last.Remote {
+		t.Errorf("scrolled-in job = %+v", last)
+	}
+}
+
+func TestYCombinatorParser(t *testing.T) {
+	if !browser.Available() {
+		// CI sets REQUIRE_BROWSER so the only headless parser can't silently go untested
+		if os.Getenv("REQUIRE_BROWSER") != "" {
+			t.Fatal("no Chrome/Chromium binary found and REQUIRE_BROWSER is set")
+		}
+		t.Skip("no Chrome/Chromium binary found")
+	}
+	replay(t, "ycombinator")
+	start := time.Now()
+
+	jobs, err := (&YCombinatorParser{}).Parse("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "ycombinator", jobs, start)
+}
+
+func TestFeedDefinitionIDPrefix(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, yaml string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(yaml), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	defs, err := LoadFeedDefinitions(write("default.yaml", "- name: We Work Remotely\n  urls: [https://example.com/a.rss]\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if defs[0].IDPrefix != "we-work-remotely" {
+		t.Errorf("default prefix = %q, want a slug of the name", defs[0].IDPrefix)
+	}
+
+	// LinkedIn already registers "li"
+	clash := write("clash.yaml", "- name: Clash\n  idPrefix: LI\n  urls: [https://example.com/b.rss]\n")
+	if err := RegisterFeeds(clash); err == nil {
+		t.Error("feed reusing a built-in parser's ID prefix should be rejected")
+	}
+}
+
+func TestSiteDefinitionIDPrefix(t *testing.T) {
+	dir := t.TempDir()
+	site := func(file, name, prefix string) {
+		yaml := "name: " + name + "\nstartUrls: [https://example.com/jobs]\ncard: .job\nfields:\n  title: {selector: h2}\n  url: {selector: a, attr: href}\n"
+		if prefix != "" {
+			yaml += "idPrefix: " + prefix + "\n"
+		}
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(yaml), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	site("a.yaml", "Remote OK Clone", "")
+	defs, err := LoadSiteDefinitions(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if defs[0].IDPrefix != "remote-ok-clone" {
+		t.Errorf("default prefix = %q, want a slug of the name", defs[0].IDPrefix)
+	}
+
+	site("b.yaml", "Other Board", "Remote-OK-Clone")
+	if _, err := LoadSiteDefinitions(dir); err == nil {
+		t.Error("two sites sharing an ID prefix should be rejected")
+	}
+}
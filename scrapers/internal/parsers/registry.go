@@ -0,0 +1,175 @@
+package parsers
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/groot34/job-aggregator/scraper/internal/fetch"
+)
+
+// Info describes a registered parser and what it is capable of
+type Info struct {
+	Name        string
+	IDPrefix    string // prefix of every job ID, also accepted as a short name by --sources
+	Headless    bool   // needs a Chrome/Chromium binary
+	Pagination  bool   // follows listing pages beyond the first
+	DetailPages bool   // visits each job's own page for extra fields
+	RateLimit   *fetch.Limit
+	New         func() Parser
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Info)
+)
+
+// Register adds a parser to the registry; built-in parsers call it from init.
+// A parser's RateLimit becomes the fetcher's default for its domain.
+func Register(info Info) {
+	if err := register(info); err != nil {
+		panic("parsers: " + err.Error())
+	}
+}
+
+func register(info Info) error {
+	if info.Name == "" || info.New == nil {
+		return fmt.Errorf("a parser needs a Name and a New func")
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	key := strings.ToLower(info.Name)
+	if _, dup := registry[key]; dup {
+		return fmt.Errorf("a parser named %s is already registered", info.Name)
+	}
+	// Job IDs and --sources short names rely on prefixes being unique
+	if info.IDPrefix == "" {
+		return fmt.Errorf("%s has no ID prefix", info.Name)
+	}
+	for _, other := range registry {
+		if strings.EqualFold(other.IDPrefix, info.IDPrefix) {
+			return fmt.Errorf("ID prefix %q is already used by %s", info.IDPrefix, other.Name)
+		}
+	}
+	registry[key] = info
+
+	if info.RateLimit != nil {
+		fetch.RegisterLimit(*info.RateLimit)
+	}
+	return nil
+}
+
+// Registered returns every registered parser sorted by name
+func Registered() []Info {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	infos := make([]Info, 0, len(registry))
+	for _, info := range registry {
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return strings.ToLower(infos[i].Name) < strings.ToLower(infos[j].Name)
+	})
+	return infos
+}
+
+// DetailPagesEnabled reports whether DETAIL_PAGES, a comma-separated list of
+// sources by name or ID prefix, opts source into visiting every job's own page
+// for the fields its listing leaves out
+func DetailPagesEnabled(source string) bool {
+	for _, name := range splitList(os.Getenv("DETAIL_PAGES")) {
+		if info, ok := Lookup(name); ok && strings.EqualFold(info.Name, source) {
+			return true
+		}
+	}
+	return false
+}
+
+// Lookup finds a parser by name or ID prefix, ignoring case
+func Lookup(name string) (Info, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, info := range Registered() {
+		if strings.ToLower(info.Name) == name || strings.ToLower(info.IDPrefix) == name {
+			return info, true
+		}
+	}
+	return Info{}, false
+}
+
+// Select builds the parsers named in a comma-separated list such as
+// "linkedin,yc"; an empty list selects every registered parser
+func Select(list string) ([]Parser, error) {
+	var infos []Info
+	if strings.TrimSpace(list) == "" {
+		infos = Registered()
+	} else {
+		seen := make(map[string]bool)
+		for _, name := range strings.Split(list, ",") {
+			if strings.TrimSpace(name) == "" {
+				continue
+			}
+			info, ok := Lookup(name)
+			if !ok {
+				return nil, fmt.Errorf("unknown source %q (see `scraper sources list`)", strings.TrimSpace(name))
+			}
+			if !seen[info.Name] {
+				seen[info.Name] = true
+				infos = append(infos, info)
+			}
+		}
+	}
+
+	result := make([]Parser, 0, len(infos))
+	for _, info := range infos {
+		result = append(result, info.New())
+	}
+	return result, nil
+}
+
+// RegisterSites registers a SelectorParser for every definition in dir
+func RegisterSites(dir string) error {
+	defs, err := LoadSiteDefinitions(dir)
+	if err != nil {
+		return err
+	}
+	for _, def := range defs {
+		err := register(Info{
+			Name:        def.Name,
+			IDPrefix:    def.IDPrefix,
+			Pagination:  def.Pagination != nil,
+			DetailPages: def.DetailPages,
+			New:         func() Parser { return &SelectorParser{Def: def} },
+		})
+		if err != nil {
+			return fmt.Errorf("site definition %s: %v", def.Name, err)
+		}
+	}
+	return nil
+}
+
+// RegisterFeeds registers a FeedParser for every definition in the YAML file
+// at path; a missing file just means no feeds
+func RegisterFeeds(path string) error {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	defs, err := LoadFeedDefinitions(path)
+	if err != nil {
+		return err
+	}
+	for _, def := range defs {
+		err := register(Info{
+			Name:     def.Name,
+			IDPrefix: def.IDPrefix,
+			New:      func() Parser { return &FeedParser{Def: def} },
+		})
+		if err != nil {
+			return fmt.Errorf("feed definition %s: %v", def.Name, err)
+		}
+	}
+	return nil
+}
@@ -1,21 +1,48 @@
 package parsers
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/PuerkitoBio/goquery"
 	"github.com/gocolly/colly/v2"
+	"github.com/groot34/job-aggregator/scraper/internal/browser"
+	"github.com/groot34/job-aggregator/scraper/internal/fetch"
+	"github.com/groot34/job-aggregator/scraper/internal/health"
 	"github.com/groot34/job-aggregator/scraper/internal/models"
 )
 
 type WellfoundParser struct{}
 
+func init() {
+	Register(Info{
+		Name:      "Wellfound",
+		IDPrefix:  "wf",
+		RateLimit: &fetch.Limit{DomainGlob: "*wellfound.com", Delay: 3 * time.Second, RandomDelay: 2 * time.Second, Parallelism: 1},
+		New:       func() Parser { return &WellfoundParser{} },
+	})
+}
+
 func (p *WellfoundParser) Name() string {
 	return "Wellfound"
 }
 
+// Expectations for the SSR role page; it is often blocked, so keep the bar low
+func (p *WellfoundParser) Expectations() health.Expectations {
+	return health.Expectations{
+		MinJobs:   1,
+		FillRates: map[string]float64{"title": 1, "company": 0.8},
+	}
+}
+
 func (p *WellfoundParser) Parse(arg string) ([]models.Job, error) {
+	return collect(p, arg)
+}
+
+// Stream emits each job card as the page is parsed
+func (p *WellfoundParser) Stream(arg string, emit Emit) error {
 	fmt.Println("🔌 Fetching jobs from Wellfound...")
 
 	// Wellfound is very dynamic (React).
@@ -23,58 +50,90 @@ func (p *WellfoundParser) Parse(arg string) ([]models.Job, error) {
 	// But public landing pages sometimes have SSR content.
 	targetURL := "https://wellfound.com/role/software-engineer"
 
-	var jobs []models.Job
+	out := &emitter{emit: emit}
 
-	c := colly.NewCollector(
-		colly.UserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"),
-	)
+	c := fetch.NewCollector(p.Name(), fetch.WithHeaders(map[string]string{
+		"Accept":  "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8,application/signed-exchange;v=b3;q=0.7",
+		"Referer": "https://google.com",
+	}))
 
-	c.OnRequest(func(r *colly.Request) {
-		r.Headers.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8,application/signed-exchange;v=b3;q=0.7")
-		r.Headers.Set("Accept-Language", "en-US,en;q=0.9")
-		r.Headers.Set("Referer", "https://google.com")
-		r.Headers.Set("Upgrade-Insecure-Requests", "1")
-		fmt.Println("Visiting", r.URL)
+	c.OnHTML(wellfoundCard, func(e *colly.HTMLElement) {
+		if job, ok := wellfoundJob(e.DOM); ok {
+			out.send(job)
+		}
 	})
 
-	c.OnHTML("div[data-test='JobListItem']", func(e *colly.HTMLElement) {
-		title := e.ChildText("h2") // Often h2 or similar
-		company := e.ChildText("div[data-test='StartupName']")
-		link := e.ChildAttr("a", "href")
+	err := c.Visit(targetURL)
+	if err != nil {
+		fmt.Printf("❌ Wellfound Scrape Error: %v\n", err)
+	}
 
-		if title == "" {
-			return
+	// The listing is usually rendered client-side or blocked for plain HTTP
+	// clients; a real browser gets further
+	if out.n == 0 && !out.stopped() && browser.Available() {
+		fmt.Println("🔁 Wellfound returned no jobs, retrying with headless browser...")
+		if err := p.parseRendered(targetURL, out); err != nil {
+			fmt.Printf("❌ Wellfound Headless Error: %v\n", err)
+			return out.err
 		}
+	}
 
-		// Wellfound URLs are relative often
-		if !strings.HasPrefix(link, "http") {
-			link = "https://wellfound.com" + link
-		}
+	fmt.Printf("✅ Found %d jobs from Wellfound\n", out.n)
+	return out.err
+}
 
-		job := models.Job{
-			ID:          "wf-" + getIDFromURL(link),
-			Title:       strings.TrimSpace(title),
-			Company:     strings.TrimSpace(company),
-			URL:         link,
-			Source:      "Wellfound",
-			PostedAt:    time.Now(),
-			ScrapedAt:   time.Now(),
-			Description: "View on Wellfound",
-		}
+// wellfoundCard matches one job in both the server-rendered and browser-rendered page
+const wellfoundCard = "div[data-test='JobListItem']"
 
-		jobs = append(jobs, job)
-	})
+// parseRendered loads the page in the shared browser pool and emits the same cards
+func (p *WellfoundParser) parseRendered(targetURL string, out *emitter) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 45*time.Second)
+	defer cancel()
+
+	html, err := browser.Default().Render(ctx, targetURL,
+		browser.WaitSelectorCount(wellfoundCard, 1),
+		browser.WaitNetworkIdle(500*time.Millisecond),
+	)
+	if err != nil {
+		return err
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return fmt.Errorf("failed to parse rendered page: %v", err)
+	}
 
-	c.OnRequest(func(r *colly.Request) {
-		fmt.Println("Visiting", r.URL)
+	doc.Find(wellfoundCard).EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		if job, ok := wellfoundJob(s); ok {
+			return out.send(job)
+		}
+		return true
 	})
+	return nil
+}
 
-	err := c.Visit(targetURL)
-	if err != nil {
-		fmt.Printf("❌ Wellfound Scrape Error: %v\n", err)
-		return nil, nil
+// wellfoundJob extracts a job from one card
+func wellfoundJob(s *goquery.Selection) (models.Job, bool) {
+	title := strings.TrimSpace(s.Find("h2").Text()) // Often h2 or similar
+	company := strings.TrimSpace(s.Find("div[data-test='StartupName']").Text())
+	link, _ := s.Find("a").Attr("href")
+
+	if title == "" {
+		return models.Job{}, false
+	}
+
+	// Wellfound URLs are relative often
+	if !strings.HasPrefix(link, "http") {
+		link = "https://wellfound.com" + link
 	}
 
-	fmt.Printf("✅ Found %d jobs from Wellfound\n", len(jobs))
-	return jobs, nil
+	return models.Job{
+		ID:          "wf-" + getIDFromURL(link),
+		Title:       title,
+		Company:     company,
+		URL:         link,
+		Source:      "Wellfound",
+		PostedAt:    time.Now(),
+		ScrapedAt:   time.Now(),
+		Description: "View on Wellfound",
+	}, true
 }
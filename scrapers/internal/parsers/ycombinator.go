@@ -3,39 +3,61 @@ package parsers
 import (
 	"context"
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/chromedp/chromedp"
+	"github.com/groot34/job-aggregator/scraper/internal/browser"
+	"github.com/groot34/job-aggregator/scraper/internal/health"
 	"github.com/groot34/job-aggregator/scraper/internal/models"
 )
 
 type YCombinatorParser struct{}
 
+// ycJobLinks matches one link per job posting on the listing page
+const ycJobLinks = `a[href*="/companies/"][href*="/jobs/"]`
+
+func init() {
+	Register(Info{
+		Name:     "YCombinator",
+		IDPrefix: "yc",
+		Headless: true,
+		New:      func() Parser { return &YCombinatorParser{} },
+	})
+}
+
 func (p *YCombinatorParser) Name() string {
 	return "YCombinator"
 }
 
+// Expectations for the rendered role page; the JS extractor already requires a company
+func (p *YCombinatorParser) Expectations() health.Expectations {
+	return health.Expectations{
+		MinJobs:   10,
+		FillRates: map[string]float64{"title": 1, "company": 1, "url": 1, "location": 0.5},
+	}
+}
+
 func (p *YCombinatorParser) Parse(arg string) ([]models.Job, error) {
+	return collect(p, arg)
+}
+
+// Stream emits the listing's jobs once the page has finished rendering
+func (p *YCombinatorParser) Stream(arg string, emit Emit) error {
 	fmt.Println("🔌 Fetching jobs from Y Combinator (using headless browser)...")
 
 	targetURL := "https://www.ycombinator.com/jobs/role/software-engineer"
 
-	// Create chromedp context
-	opts := append(chromedp.DefaultExecAllocatorOptions[:],
-		chromedp.Flag("headless", true),
-		chromedp.Flag("disable-gpu", true),
-		chromedp.Flag("no-sandbox", true),
-	)
-
-	allocCtx, cancel := chromedp.NewExecAllocator(context.Background(), opts...)
-	defer cancel()
-
-	ctx, cancel := chromedp.NewContext(allocCtx)
-	defer cancel()
+	// The listing grows as you scroll; YC_MAX_SCROLLS caps how far we go
+	maxScrolls := 20
+	if n, err := strconv.Atoi(os.Getenv("YC_MAX_SCROLLS")); err == nil && n >= 0 {
+		maxScrolls = n
+	}
+	const settle = 3 * time.Second
 
-	// Set timeout
-	ctx, cancel = context.WithTimeout(ctx, 45*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 45*time.Second+time.Duration(maxScrolls)*settle)
 	defer cancel()
 
 	// JavaScript to extract job data
@@ -92,20 +114,41 @@ func (p *YCombinatorParser) Parse(arg string) ([]models.Job, error) {
 	})();
 	`
 
-	var jobsData []map[string]interface{}
-
-	err := chromedp.Run(ctx,
-		chromedp.Navigate(targetURL),
+	pool := browser.Default()
+	html, err := pool.Render(ctx, targetURL,
 		chromedp.WaitVisible(`a[href*="/companies/"]`, chromedp.ByQuery),
-		chromedp.Sleep(3*time.Second),
-		chromedp.Evaluate(jsCode, &jobsData),
+		browser.WaitNetworkIdle(500*time.Millisecond),
+		browser.LoadMore(ycJobLinks, maxScrolls, settle),
 	)
-
 	if err != nil {
-		return nil, fmt.Errorf("failed to scrape YC: %v", err)
+		return fmt.Errorf("failed to scrape YC: %v", err)
+	}
+
+	// The page ships its data as Inertia props, but only for the initial render;
+	// jobs revealed by scrolling exist only as links, so both sources are merged
+	jobs, ok := ycJobsFromProps(html)
+	var jobsData []map[string]interface{}
+	if err := pool.Evaluate(ctx, html, jsCode, &jobsData); err != nil {
+		if !ok {
+			return fmt.Errorf("failed to scrape YC: %v", err)
+		}
+		fmt.Printf("⚠️  YC DOM extraction failed, using embedded job data only: %v\n", err)
 	}
+	jobs = ycMerge(jobs, ycJobsFromDOM(jobsData))
 
-	// Convert to Job models
+	out := &emitter{emit: emit}
+	for _, j := range jobs {
+		if !out.send(j) {
+			return out.err
+		}
+	}
+
+	fmt.Printf("✅ Found %d jobs from Y Combinator\n", out.n)
+	return nil
+}
+
+// ycJobsFromDOM converts the objects returned by the DOM extraction script
+func ycJobsFromDOM(jobsData []map[string]interface{}) []models.Job {
 	var jobs []models.Job
 
 	for _, data := range jobsData {
@@ -131,15 +174,11 @@ func (p *YCombinatorParser) Parse(arg string) ([]models.Job, error) {
 			}
 		}
 
-		// Generate unique ID
-		jobID := "yc-" + strings.ReplaceAll(url, "https://www.ycombinator.com/companies/", "")
-		jobID = strings.ReplaceAll(jobID, "/", "-")
-
 		// Check if remote
 		isRemote := strings.Contains(strings.ToLower(location), "remote")
 
 		job := models.Job{
-			ID:          jobID,
+			ID:          ycJobID(url),
 			Title:       strings.TrimSpace(title),
 			Company:     strings.TrimSpace(company),
 			Location:    strings.TrimSpace(location),
@@ -155,7 +194,11 @@ func (p *YCombinatorParser) Parse(arg string) ([]models.Job, error) {
 
 		jobs = append(jobs, job)
 	}
+	return jobs
+}
 
-	fmt.Printf("✅ Found %d jobs from Y Combinator\n", len(jobs))
-	return jobs, nil
+// ycJobID derives a stable ID from the posting's URL
+func ycJobID(url string) string {
+	jobID := "yc-" + strings.ReplaceAll(url, "https://www.ycombinator.com/companies/", "")
+	return strings.ReplaceAll(jobID, "/", "-")
 }
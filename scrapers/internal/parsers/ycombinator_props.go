@@ -0,0 +1,221 @@
+package parsers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+)
+
+// YC pages are Inertia apps: the server renders the page's props as JSON into
+// the root element's data-page attribute. Key names have shifted over time, so
+// every field is looked up under each name we've seen it use.
+var (
+	ycTitleKeys    = []string{"title", "jobTitle"}
+	ycURLKeys      = []string{"url", "jobUrl", "path"}
+	ycCompanyKeys  = []string{"companyName", "company"}
+	ycBatchKeys    = []string{"companyBatchName", "companyBatch", "batch"}
+	ycLocationKeys = []string{"locations", "location", "prettyLocation"}
+	ycSalaryKeys   = []string{"salaryRange", "prettySalaryRange", "salary"}
+	ycEquityKeys   = []string{"equityRange", "prettyEquityRange", "equity"}
+	ycVisaKeys     = []string{"visa", "prettyVisa"}
+	ycRemoteKeys   = []string{"remote", "remoteOk", "isRemote"}
+	ycSummaryKeys  = []string{"companyOneLiner", "oneLiner", "description"}
+)
+
+// ycJobsFromProps extracts postings from the embedded page props; ok is false
+// when the page carries no recognisable job data
+func ycJobsFromProps(html string) (jobs []models.Job, ok bool) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return nil, false
+	}
+
+	var payloads []string
+	doc.Find("[data-page]").Each(func(_ int, s *goquery.Selection) {
+		payloads = append(payloads, s.AttrOr("data-page", ""))
+	})
+	doc.Find(`script[type="application/json"]`).Each(func(_ int, s *goquery.Selection) {
+		payloads = append(payloads, s.Text())
+	})
+
+	seen := make(map[string]bool)
+	for _, payload := range payloads {
+		var props interface{}
+		if err := json.Unmarshal([]byte(payload), &props); err != nil {
+			continue
+		}
+		for _, posting := range ycPostings(props) {
+			job, valid := ycJobFromPosting(posting)
+			if !valid || seen[job.ID] {
+				continue
+			}
+			seen[job.ID] = true
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, len(jobs) > 0
+}
+
+// ycMerge appends the DOM jobs missing from the props. Props carry richer
+// fields, so they win for jobs found in both.
+func ycMerge(props, dom []models.Job) []models.Job {
+	seen := make(map[string]bool, len(props))
+	for _, j := range props {
+		seen[j.ID] = true
+	}
+	for _, j := range dom {
+		if !seen[j.ID] {
+			seen[j.ID] = true
+			props = append(props, j)
+		}
+	}
+	return props
+}
+
+// ycPostings walks the props and returns every object that looks like a job posting
+func ycPostings(v interface{}) []map[string]interface{} {
+	var found []map[string]interface{}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if ycString(v, ycTitleKeys) != "" && ycCompany(v) != "" {
+			return []map[string]interface{}{v}
+		}
+		// Walk keys in order so the job order is stable between runs
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			found = append(found, ycPostings(v[k])...)
+		}
+	case []interface{}:
+		for _, child := range v {
+			found = append(found, ycPostings(child)...)
+		}
+	}
+	return found
+}
+
+func ycJobFromPosting(p map[string]interface{}) (models.Job, bool) {
+	title := ycString(p, ycTitleKeys)
+	company := ycCompany(p)
+	url := ycString(p, ycURLKeys)
+	if title == "" || company == "" || url == "" {
+		return models.Job{}, false
+	}
+	if !strings.HasPrefix(url, "http") {
+		url = "https://www.ycombinator.com" + url
+	}
+
+	locations := ycStrings(p, ycLocationKeys)
+	location := strings.Join(locations, " / ")
+
+	remote := strings.Contains(strings.ToLower(location), "remote")
+	switch r := ycValue(p, ycRemoteKeys).(type) {
+	case bool:
+		remote = remote || r
+	case string:
+		// "yes", "only" and "hybrid" all mean remote work is possible
+		r = strings.ToLower(r)
+		remote = remote || (r != "" && r != "no" && r != "none" && r != "false")
+	}
+
+	salary := ycString(p, ycSalaryKeys)
+	if equity := ycString(p, ycEquityKeys); equity != "" {
+		if salary != "" {
+			salary += ", "
+		}
+		salary += equity + " equity"
+	}
+
+	tags := []string{"startup", "yc"}
+	batch := ycString(p, ycBatchKeys)
+	if batch == "" {
+		if c, ok := p["company"].(map[string]interface{}); ok {
+			batch = ycString(c, ycBatchKeys)
+		}
+	}
+	if batch != "" {
+		tags = append(tags, "YC-"+batch)
+	}
+
+	description := ycString(p, ycSummaryKeys)
+	if visa := ycString(p, ycVisaKeys); visa != "" {
+		lower := strings.ToLower(visa)
+		if strings.Contains(lower, "sponsor") && !strings.Contains(lower, "not") && !strings.Contains(lower, "no ") {
+			tags = append(tags, "visa-sponsorship")
+		}
+		if description != "" {
+			description = strings.TrimSuffix(description, ".") + ". "
+		}
+		description += fmt.Sprintf("Visa: %s.", visa)
+	}
+
+	return models.Job{
+		ID:          ycJobID(url),
+		Title:       title,
+		Company:     company,
+		Location:    location,
+		Description: description,
+		URL:         url,
+		Source:      "YCombinator",
+		PostedAt:    time.Now(),
+		ScrapedAt:   time.Now(),
+		Remote:      remote,
+		Salary:      salary,
+		Tags:        tags,
+	}, true
+}
+
+// ycCompany handles both a flat companyName and a nested company object
+func ycCompany(p map[string]interface{}) string {
+	if c, ok := p["company"].(map[string]interface{}); ok {
+		return ycString(c, []string{"name"})
+	}
+	return ycString(p, ycCompanyKeys)
+}
+
+func ycValue(p map[string]interface{}, keys []string) interface{} {
+	for _, k := range keys {
+		if v, ok := p[k]; ok && v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+func ycString(p map[string]interface{}, keys []string) string {
+	switch v := ycValue(p, keys).(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case bool:
+		if v {
+			return "yes"
+		}
+	}
+	return ""
+}
+
+// ycStrings accepts either a list of strings or a single string
+func ycStrings(p map[string]interface{}, keys []string) []string {
+	var out []string
+	switch v := ycValue(p, keys).(type) {
+	case string:
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+				out = append(out, strings.TrimSpace(s))
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,179 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+)
+
+// Stage is one step of the pipeline. Run reads jobs from in until it is
+// closed and sends the jobs it keeps to out; the pipeline closes out once Run
+// returns. Sends should go through Send so a failing stage elsewhere unblocks it.
+type Stage interface {
+	Name() string
+	Run(ctx context.Context, in <-chan models.Job, out chan<- models.Job) error
+}
+
+// Send delivers j to out unless ctx is cancelled first
+func Send(ctx context.Context, out chan<- models.Job, j models.Job) error {
+	select {
+	case out <- j:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// StageStats counts the jobs that went into and came out of a stage
+type StageStats struct {
+	Name     string
+	In       int64
+	Out      int64
+	Duration time.Duration // from the stage's start until it finished
+}
+
+// Pipeline runs stages in order, each in its own goroutine, connected by
+// bounded channels: a slow stage fills its input buffer and so holds up the
+// stages before it instead of letting jobs pile up in memory
+type Pipeline struct {
+	Stages []Stage
+	Buffer int // capacity of the channel feeding each stage
+
+	stats []*StageStats
+}
+
+// New returns a pipeline of stages with the given channel buffer
+func New(buffer int, stages ...Stage) *Pipeline {
+	return &Pipeline{Stages: stages, Buffer: buffer}
+}
+
+// Start feeds in through the stages. The returned channel must be drained,
+// and in must eventually be closed even if a stage fails; wait then reports
+// the first stage error once every stage has stopped.
+func (p *Pipeline) Start(ctx context.Context, in <-chan models.Job) (out <-chan models.Job, wait func() error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	fail := func(name string, err error) {
+		errOnce.Do(func() {
+			firstErr = fmt.Errorf("%s stage: %v", name, err)
+			cancel()
+		})
+	}
+
+	// counts[0] is jobs fed in; counts[i+1] is jobs out of stage i
+	counts := make([]int64, len(p.Stages)+1)
+	src := p.forward(ctx, &wg, in, &counts[0])
+
+	p.stats = make([]*StageStats, len(p.Stages))
+	for i, s := range p.Stages {
+		stats := &StageStats{Name: s.Name()}
+		p.stats[i] = stats
+
+		stageOut := make(chan models.Job, p.Buffer)
+		wg.Add(1)
+		go func(s Stage, src <-chan models.Job) {
+			defer wg.Done()
+			defer close(stageOut)
+
+			start := time.Now()
+			if err := s.Run(ctx, src, stageOut); err != nil {
+				fail(s.Name(), err)
+			}
+			stats.Duration = time.Since(start)
+			// A stage that stopped early leaves its input unread
+			for range src {
+			}
+		}(s, src)
+
+		src = p.forward(ctx, &wg, stageOut, &counts[i+1])
+	}
+
+	return src, func() error {
+		wg.Wait()
+		cancel()
+		for i, stats := range p.stats {
+			stats.In, stats.Out = counts[i], counts[i+1]
+		}
+		return firstErr
+	}
+}
+
+// forward copies src to a new channel, counting jobs. Once ctx is cancelled it
+// discards the rest of src so the goroutines feeding it can finish.
+func (p *Pipeline) forward(ctx context.Context, wg *sync.WaitGroup, src <-chan models.Job, n *int64) <-chan models.Job {
+	dst := make(chan models.Job)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(dst)
+		for j := range src {
+			if Send(ctx, dst, j) != nil {
+				for range src {
+				}
+				return
+			}
+			atomic.AddInt64(n, 1)
+		}
+	}()
+	return dst
+}
+
+// Run feeds in through the stages and collects what comes out the end
+func (p *Pipeline) Run(ctx context.Context, in <-chan models.Job) ([]models.Job, error) {
+	out, wait := p.Start(ctx, in)
+	var jobs []models.Job
+	for j := range out {
+		jobs = append(jobs, j)
+	}
+	return jobs, wait()
+}
+
+// Stats returns each stage's counts from the last finished run, in stage order
+func (p *Pipeline) Stats() []StageStats {
+	out := make([]StageStats, len(p.stats))
+	for i, s := range p.stats {
+		out[i] = *s
+	}
+	return out
+}
+
+// Summary renders the stats as "normalize 120→120, filter 120→98, ..."
+func (p *Pipeline) Summary() string {
+	var parts []string
+	for _, s := range p.Stats() {
+		parts = append(parts, fmt.Sprintf("%s %d→%d", s.Name, s.In, s.Out))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Select picks stages from available in the order named by a comma-separated list
+func Select(available map[string]Stage, list string) ([]Stage, error) {
+	var stages []Stage
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		s, ok := available[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown pipeline stage %q", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("pipeline stage %q listed twice", name)
+		}
+		seen[name] = true
+		stages = append(stages, s)
+	}
+	return stages, nil
+}
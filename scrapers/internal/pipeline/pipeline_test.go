@@ -0,0 +1,232 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/filter"
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+)
+
+// feed returns a closed channel holding jobs with the given IDs
+func feed(ids ...string) <-chan models.Job {
+	in := make(chan models.Job, len(ids))
+	for _, id := range ids {
+		in <- models.Job{ID: id, Title: id}
+	}
+	close(in)
+	return in
+}
+
+// runStage runs a single stage to completion and returns the IDs it emitted
+func runStage(t *testing.T, s Stage, in []models.Job) []string {
+	t.Helper()
+
+	src := make(chan models.Job, len(in))
+	for _, j := range in {
+		src <- j
+	}
+	close(src)
+	out := make(chan models.Job, len(in))
+	if err := s.Run(context.Background(), src, out); err != nil {
+		t.Fatal(err)
+	}
+	close(out)
+
+	var ids []string
+	for j := range out {
+		ids = append(ids, j.ID)
+	}
+	return ids
+}
+
+func ids(jobs []models.Job) []string {
+	var out []string
+	for _, j := range jobs {
+		out = append(out, j.ID)
+	}
+	return out
+}
+
+func TestDedupe(t *testing.T) {
+	in := []models.Job{{ID: "a"}, {ID: "b"}, {ID: "a"}, {ID: "c"}, {ID: "b"}}
+	if got, want := runStage(t, Dedupe(), in), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	in := []models.Job{
+		{ID: "dev", Title: "Senior Backend Engineer", Description: "Go, PostgreSQL and Kubernetes"},
+		{ID: "chef", Title: "Head Chef", Description: "Run our restaurant kitchen"},
+	}
+	if got, want := runStage(t, Classify(), in), []string{"dev"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestBatchSeesWholeRun(t *testing.T) {
+	reverse := Batch("reverse", func(_ context.Context, jobs []models.Job) ([]models.Job, error) {
+		for i, j := 0, len(jobs)-1; i < j; i, j = i+1, j-1 {
+			jobs[i], jobs[j] = jobs[j], jobs[i]
+		}
+		return jobs, nil
+	})
+	in := []models.Job{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+	if got, want := runStage(t, reverse, in), []string{"c", "b", "a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestPipelineOrderAndStats(t *testing.T) {
+	dropB := Map("drop-b", func(j models.Job) (models.Job, bool) { return j, j.ID != "b" })
+	upper := Map("upper", func(j models.Job) (models.Job, bool) {
+		j.Title = strings.ToUpper(j.Title)
+		return j, true
+	})
+
+	p := New(0, dropB, Dedupe(), upper)
+	jobs, err := p.Run(context.Background(), feed("a", "b", "c", "a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ids(jobs), []string{"a", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if jobs[0].Title != "A" {
+		t.Errorf("title = %q, want stages applied in order", jobs[0].Title)
+	}
+	if got, want := p.Summary(), "drop-b 4→3, dedupe 3→2, upper 2→2"; got != want {
+		t.Errorf("summary = %q, want %q", got, want)
+	}
+}
+
+func TestPipelineStageError(t *testing.T) {
+	failing := failAfter{n: 3}
+
+	// An unbuffered producer must not block forever once a stage has failed
+	in := make(chan models.Job)
+	go func() {
+		defer close(in)
+		for i := 0; i < 100; i++ {
+			in <- models.Job{ID: strconv.Itoa(i)}
+		}
+	}()
+
+	_, err := New(0, Dedupe(), failing, Dedupe()).Run(context.Background(), in)
+	if err == nil || !strings.Contains(err.Error(), "failing stage") {
+		t.Fatalf("err = %v, want failing stage error", err)
+	}
+}
+
+// failAfter errors out after passing n jobs, leaving the rest of its input unread
+type failAfter struct{ n int }
+
+func (s failAfter) Name() string { return "failing" }
+
+func (s failAfter) Run(ctx context.Context, in <-chan models.Job, out chan<- models.Job) error {
+	for j := range in {
+		if s.n == 0 {
+			return errors.New("boom")
+		}
+		s.n--
+		if err := Send(ctx, out, j); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestSelect(t *testing.T) {
+	available := map[string]Stage{"dedupe": Dedupe(), "classify": Classify()}
+
+	stages, err := Select(available, " Classify, dedupe ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stages) != 2 || stages[0].Name() != "classify" || stages[1].Name() != "dedupe" {
+		t.Errorf("got %v, want classify then dedupe", stages)
+	}
+
+	if _, err := Select(available, "dedupe,publish"); err == nil {
+		t.Error("expected an error for an unknown stage")
+	}
+	if _, err := Select(available, "dedupe,dedupe"); err == nil {
+		t.Error("expected an error for a repeated stage")
+	}
+}
+
+func TestPublishBatches(t *testing.T) {
+	var sizes []int
+	publish := Publish(2, func(jobs []models.Job) error {
+		sizes = append(sizes, len(jobs))
+		return nil
+	}, nil)
+	in := []models.Job{{ID: "a"}, {ID: "b"}, {ID: "c"}, {ID: "d"}, {ID: "e"}}
+	if got, want := runStage(t, publish, in), []string{"a", "b", "c", "d", "e"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("passed on %v, want %v", got, want)
+	}
+	if want := []int{2, 2, 1}; !reflect.DeepEqual(sizes, want) {
+		t.Errorf("batch sizes = %v, want %v", sizes, want)
+	}
+}
+
+func TestFilterStreams(t *testing.T) {
+	f, err := filter.New(filter.Config{Confidential: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report filter.Report
+	in := make(chan models.Job)
+	out, wait := New(0, Filter(f, time.Now(), nil, func(r filter.Report) { report = r })).Start(context.Background(), in)
+
+	// A kept job comes out before the input is closed
+	in <- models.Job{ID: "hidden", Company: "Confidential"}
+	in <- models.Job{ID: "ok", Company: "Acme"}
+	if j := <-out; j.ID != "ok" {
+		t.Errorf("got %s, want ok", j.ID)
+	}
+	close(in)
+	for range out {
+	}
+	if err := wait(); err != nil {
+		t.Fatal(err)
+	}
+	if report.Checked != 2 || len(report.Dropped) != 1 {
+		t.Errorf("report = %+v, want 2 checked and 1 dropped", report)
+	}
+}
+
+func TestEnrichKeepsParserTags(t *testing.T) {
+	in := make(chan models.Job, 1)
+	in <- models.Job{ID: "a", Title: "Backend Engineer", Description: "We use Python and Docker.", Tags: []string{"YC-W24", "visa-sponsorship", "python"}}
+	close(in)
+	out, wait := New(0, Enrich()).Start(context.Background(), in)
+	j := <-out
+	for range out {
+	}
+	if err := wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := j.Tags[:3]; !reflect.DeepEqual(got, []string{"YC-W24", "visa-sponsorship", "python"}) {
+		t.Errorf("parser tags = %v, want them kept in front", got)
+	}
+	var docker, pythons int
+	for _, tag := range j.Tags {
+		switch strings.ToLower(tag) {
+		case "docker":
+			docker++
+		case "python":
+			pythons++
+		}
+	}
+	if docker != 1 || pythons != 1 {
+		t.Errorf("tags = %v, want Docker added once and Python not repeated", j.Tags)
+	}
+}
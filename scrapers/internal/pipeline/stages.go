@@ -0,0 +1,224 @@
+package pipeline
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/company"
+	"github.com/groot34/job-aggregator/scraper/internal/filter"
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+	"github.com/groot34/job-aggregator/scraper/internal/skills"
+)
+
+// mapStage applies fn to each job as it arrives
+type mapStage struct {
+	name string
+	fn   func(models.Job) (models.Job, bool)
+}
+
+// Map is a streaming stage: fn rewrites each job and reports whether to keep it
+func Map(name string, fn func(models.Job) (models.Job, bool)) Stage {
+	return mapStage{name: name, fn: fn}
+}
+
+func (s mapStage) Name() string {
+	return s.name
+}
+
+func (s mapStage) Run(ctx context.Context, in <-chan models.Job, out chan<- models.Job) error {
+	for j := range in {
+		j, keep := s.fn(j)
+		if !keep {
+			continue
+		}
+		if err := Send(ctx, out, j); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// batchStage collects every job, then hands them to fn at once
+type batchStage struct {
+	name string
+	fn   func(context.Context, []models.Job) ([]models.Job, error)
+}
+
+// Batch is a stage for steps that need the whole run, such as spotting
+// reposts across sources. It holds every job until its input closes.
+func Batch(name string, fn func(context.Context, []models.Job) ([]models.Job, error)) Stage {
+	return batchStage{name: name, fn: fn}
+}
+
+func (s batchStage) Name() string {
+	return s.name
+}
+
+func (s batchStage) Run(ctx context.Context, in <-chan models.Job, out chan<- models.Job) error {
+	var jobs []models.Job
+	for j := range in {
+		jobs = append(jobs, j)
+	}
+	jobs, err := s.fn(ctx, jobs)
+	if err != nil {
+		return err
+	}
+	for _, j := range jobs {
+		if err := Send(ctx, out, j); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Tap passes every job through unchanged, showing each to fn on the way
+func Tap(name string, fn func(models.Job)) Stage {
+	return Map(name, func(j models.Job) (models.Job, bool) {
+		fn(j)
+		return j, true
+	})
+}
+
+// Dedupe keeps the first job with each ID; sources sometimes list a job twice
+func Dedupe() Stage {
+	seen := make(map[string]bool)
+	return Map("dedupe", func(j models.Job) (models.Job, bool) {
+		if seen[j.ID] {
+			return j, false
+		}
+		seen[j.ID] = true
+		return j, true
+	})
+}
+
+// Normalize resolves each job's company through the directory
+func Normalize(dir *company.Directory, now time.Time) Stage {
+	return Map("normalize", func(j models.Job) (models.Job, bool) {
+		dir.Apply(&j, now)
+		return j, true
+	})
+}
+
+// filterStage checks each job as it arrives and reports once its input closes
+type filterStage struct {
+	f        *filter.Filter
+	now      time.Time
+	previous map[string]string
+	report   func(filter.Report)
+}
+
+// Filter drops blocked and spam jobs as they stream past; report receives what
+// was dropped and why. previous is the last run's Report.Reposts, or nil.
+func Filter(f *filter.Filter, now time.Time, previous map[string]string, report func(filter.Report)) Stage {
+	return filterStage{f: f, now: now, previous: previous, report: report}
+}
+
+func (s filterStage) Name() string {
+	return "filter"
+}
+
+func (s filterStage) Run(ctx context.Context, in <-chan models.Job, out chan<- models.Job) error {
+	run := s.f.Start(s.now, s.previous)
+	for j := range in {
+		if !run.Check(j) {
+			continue
+		}
+		if err := Send(ctx, out, j); err != nil {
+			return err
+		}
+	}
+	// Repost groups whose survivor from last run never came pick another now
+	for _, j := range run.Finish() {
+		if err := Send(ctx, out, j); err != nil {
+			return err
+		}
+	}
+	if s.report != nil {
+		s.report(run.Report())
+	}
+	return nil
+}
+
+// Classify keeps only software jobs
+func Classify() Stage {
+	return Map("classify", func(j models.Job) (models.Job, bool) {
+		return j, skills.IsSoftwareJob(fullText(j))
+	})
+}
+
+// Enrich adds the skills found in each job's text to its tags. Tags the parser
+// set, such as a feed's static tags or a YC batch, are kept.
+func Enrich() Stage {
+	return Map("enrich", func(j models.Job) (models.Job, bool) {
+		tags := append([]string(nil), j.Tags...)
+		have := make(map[string]bool, len(tags))
+		for _, t := range tags {
+			have[strings.ToLower(t)] = true
+		}
+		for _, skill := range skills.ExtractSkills(fullText(j)) {
+			if !have[strings.ToLower(skill)] {
+				have[strings.ToLower(skill)] = true
+				tags = append(tags, skill)
+			}
+		}
+		j.Tags = tags
+		return j, true
+	})
+}
+
+// publishStage collects jobs into batches of up to size and passes each batch
+// on once it has been handed to publish
+type publishStage struct {
+	size    int
+	publish func([]models.Job) error
+	onError func(error)
+}
+
+// Publish hands jobs to publish in batches of up to size (at least 1), so
+// early sources reach the backend while later ones are still being scraped.
+// A failed publish is reported through onError rather than stopping the later steps.
+func Publish(size int, publish func([]models.Job) error, onError func(error)) Stage {
+	if size < 1 {
+		size = 1
+	}
+	return publishStage{size: size, publish: publish, onError: onError}
+}
+
+func (s publishStage) Name() string {
+	return "publish"
+}
+
+func (s publishStage) Run(ctx context.Context, in <-chan models.Job, out chan<- models.Job) error {
+	batch := make([]models.Job, 0, s.size)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := s.publish(batch); err != nil && s.onError != nil {
+			s.onError(err)
+		}
+		for _, j := range batch {
+			if err := Send(ctx, out, j); err != nil {
+				return err
+			}
+		}
+		batch = make([]models.Job, 0, s.size)
+		return nil
+	}
+
+	for j := range in {
+		batch = append(batch, j)
+		if len(batch) == s.size {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return flush()
+}
+
+// fullText is what classification and skill extraction read
+func fullText(j models.Job) string {
+	return j.Title + " " + j.Description + " " + strings.Join(j.Tags, " ")
+}
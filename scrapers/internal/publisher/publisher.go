@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 
+	"github.com/groot34/job-aggregator/scraper/internal/lifecycle"
 	"github.com/groot34/job-aggregator/scraper/internal/models"
 )
 
@@ -17,10 +18,35 @@ func PublishJobs(jobs []models.Job) error {
 		apiUrl = "http://localhost:5000/api/jobs/batch"
 	}
 
+	if err := postJSON(apiUrl, jobs); err != nil {
+		return err
+	}
+
+	fmt.Printf("📤 Sent %d jobs to backend successfully\n", len(jobs))
+	return nil
+}
+
+// PublishEvents forwards lifecycle status changes to BACKEND_EVENTS_URL.
+// Forwarding is opt-in: nothing is sent when the variable is unset.
+func PublishEvents(events []lifecycle.Event) error {
+	apiUrl := os.Getenv("BACKEND_EVENTS_URL")
+	if apiUrl == "" || len(events) == 0 {
+		return nil
+	}
+
+	if err := postJSON(apiUrl, events); err != nil {
+		return err
+	}
+
+	fmt.Printf("📤 Sent %d lifecycle events successfully\n", len(events))
+	return nil
+}
+
+func postJSON(apiUrl string, v interface{}) error {
 	// Transform to JSON
-	payload, err := json.Marshal(jobs)
+	payload, err := json.Marshal(v)
 	if err != nil {
-		return fmt.Errorf("failed to marshal jobs: %v", err)
+		return fmt.Errorf("failed to marshal payload: %v", err)
 	}
 
 	// Send POST request
@@ -33,7 +59,5 @@ func PublishJobs(jobs []models.Job) error {
 	if resp.StatusCode != 200 && resp.StatusCode != 201 {
 		return fmt.Errorf("backend returned status: %d", resp.StatusCode)
 	}
-
-	fmt.Printf("📤 Sent %d jobs to backend successfully\n", len(jobs))
 	return nil
 }
@@ -0,0 +1,305 @@
+package scheduler
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Task is one unit of work for a source, such as running its parser
+type Task struct {
+	Source   string
+	Priority int // higher runs first; equal priorities share workers fairly between sources
+	Run      func()
+
+	seq      uint64
+	queuedAt time.Time
+}
+
+// Config bounds how much work runs at once. The caps count tasks, such as one
+// board, page or detail page of a source.
+type Config struct {
+	Workers   int // tasks running at once across every source; New treats 0 as 1
+	PerSource int // tasks running at once for one source; 0 leaves only the global cap
+
+	// Limits and Priorities override PerSource and the submitted priority for
+	// single sources, keyed by lowercase source name
+	Limits     map[string]int
+	Priorities map[string]int
+}
+
+// ConfigFromEnv reads SCHEDULER_WORKERS (default 4), SCHEDULER_PER_SOURCE
+// (default 1) and the "Source=n,..." lists SCHEDULER_LIMITS and SCHEDULER_PRIORITIES.
+// Invalid values are ignored.
+func ConfigFromEnv() Config {
+	cfg := Config{Workers: 4, PerSource: 1}
+	if n, err := strconv.Atoi(os.Getenv("SCHEDULER_WORKERS")); err == nil && n > 0 {
+		cfg.Workers = n
+	}
+	if n, err := strconv.Atoi(os.Getenv("SCHEDULER_PER_SOURCE")); err == nil && n >= 0 {
+		cfg.PerSource = n
+	}
+	cfg.Limits = sourceValues(os.Getenv("SCHEDULER_LIMITS"))
+	cfg.Priorities = sourceValues(os.Getenv("SCHEDULER_PRIORITIES"))
+	return cfg
+}
+
+// sourceValues parses "LinkedIn=1, JobPosting=3", skipping malformed pairs
+func sourceValues(s string) map[string]int {
+	values := make(map[string]int)
+	for _, pair := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			values[name] = n
+		}
+	}
+	return values
+}
+
+// SourceStats are one source's queue metrics
+type SourceStats struct {
+	Queued    int           `json:"queued"`
+	Running   int           `json:"running"`
+	Done      int           `json:"done"`
+	MaxQueued int           `json:"maxQueued"` // deepest the source's queue got
+	Waited    time.Duration `json:"waited"`    // total time its tasks spent queued
+}
+
+// Stats are queue metrics across every source
+type Stats struct {
+	Queued    int                    `json:"queued"`
+	Running   int                    `json:"running"`
+	Done      int                    `json:"done"`
+	MaxQueued int                    `json:"maxQueued"`
+	Sources   map[string]SourceStats `json:"sources"`
+}
+
+// Scheduler runs tasks on a fixed pool of workers. The next task is the
+// highest-priority one whose source is under its cap; among equal priorities
+// the source with the fewest running tasks goes first, then the one served
+// longest ago, so one source with many tasks cannot starve the rest.
+type Scheduler struct {
+	cfg Config
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	queues  map[string][]*Task // by source, priority then submission order
+	sources map[string]*SourceStats
+	served  map[string]uint64 // starts counter value at the source's last start
+	starts  uint64
+	seq     uint64
+	queued  int
+	max     int
+	closed  bool
+	pending sync.WaitGroup
+}
+
+// New starts cfg.Workers workers (at least one)
+func New(cfg Config) *Scheduler {
+	if cfg.Workers < 1 {
+		cfg.Workers = 1
+	}
+	s := &Scheduler{
+		cfg:     cfg,
+		queues:  make(map[string][]*Task),
+		sources: make(map[string]*SourceStats),
+		served:  make(map[string]uint64),
+	}
+	s.cond = sync.NewCond(&s.mu)
+	for i := 0; i < cfg.Workers; i++ {
+		go s.work()
+	}
+	return s
+}
+
+// Submit queues t; it panics once the scheduler is closed
+func (s *Scheduler) Submit(t Task) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		panic("scheduler: Submit after Close")
+	}
+
+	if p, ok := s.cfg.Priorities[strings.ToLower(t.Source)]; ok {
+		t.Priority = p
+	}
+	s.seq++
+	t.seq = s.seq
+	t.queuedAt = time.Now()
+
+	// Keep each queue ordered so its head is the task to run next
+	q := s.queues[t.Source]
+	i := sort.Search(len(q), func(i int) bool { return q[i].Priority < t.Priority })
+	q = append(q, nil)
+	copy(q[i+1:], q[i:])
+	q[i] = &t
+	s.queues[t.Source] = q
+
+	st := s.stats(t.Source)
+	st.Queued++
+	if st.Queued > st.MaxQueued {
+		st.MaxQueued = st.Queued
+	}
+	s.queued++
+	if s.queued > s.max {
+		s.max = s.queued
+	}
+
+	s.pending.Add(1)
+	s.cond.Signal()
+}
+
+// Wait blocks until every submitted task has finished
+func (s *Scheduler) Wait() {
+	s.pending.Wait()
+}
+
+// Close stops the workers once the queue is empty
+func (s *Scheduler) Close() {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	s.cond.Broadcast()
+}
+
+// Stats returns a snapshot of the queue metrics
+func (s *Scheduler) Stats() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := Stats{MaxQueued: s.max, Sources: make(map[string]SourceStats, len(s.sources))}
+	for name, st := range s.sources {
+		out.Queued += st.Queued
+		out.Running += st.Running
+		out.Done += st.Done
+		out.Sources[name] = *st
+	}
+	return out
+}
+
+// Summary renders the stats as "11 tasks on 4 workers, queue peaked at 7, longest wait LinkedIn 32s"
+func (s *Scheduler) Summary() string {
+	stats := s.Stats()
+	summary := fmt.Sprintf("%d tasks on %d workers, queue peaked at %d", stats.Done, s.cfg.Workers, stats.MaxQueued)
+
+	names := make([]string, 0, len(stats.Sources))
+	for name := range stats.Sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var slowest string
+	var longest time.Duration
+	for _, name := range names {
+		if w := stats.Sources[name].Waited; w > longest {
+			slowest, longest = name, w
+		}
+	}
+	// Waits under a second are just scheduling noise
+	if longest = longest.Round(time.Second); longest > 0 {
+		summary += fmt.Sprintf(", longest wait %s %s", slowest, longest)
+	}
+	return summary
+}
+
+// work runs tasks until the scheduler is closed and drained
+func (s *Scheduler) work() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for {
+		t := s.next()
+		if t == nil {
+			if s.closed && s.queued == 0 {
+				return
+			}
+			s.cond.Wait()
+			continue
+		}
+
+		s.mu.Unlock()
+		t.Run()
+		s.mu.Lock()
+
+		st := s.stats(t.Source)
+		st.Running--
+		st.Done++
+		s.pending.Done()
+		// A slot for t's source is free again, which may unblock a waiting worker
+		s.cond.Broadcast()
+	}
+}
+
+// next dequeues the task to start, or nil when every queued source is at its
+// cap; callers hold s.mu
+func (s *Scheduler) next() *Task {
+	var best string
+	for source, q := range s.queues {
+		if len(q) == 0 || s.atCap(source) {
+			continue
+		}
+		if best == "" || s.before(source, best) {
+			best = source
+		}
+	}
+	if best == "" {
+		return nil
+	}
+
+	q := s.queues[best]
+	t := q[0]
+	s.queues[best] = q[1:]
+
+	st := s.stats(best)
+	st.Queued--
+	st.Running++
+	st.Waited += time.Since(t.queuedAt)
+	s.queued--
+	s.starts++
+	s.served[best] = s.starts
+	return t
+}
+
+// before reports whether source a's head task should start before source b's
+func (s *Scheduler) before(a, b string) bool {
+	pa, pb := s.queues[a][0].Priority, s.queues[b][0].Priority
+	if pa != pb {
+		return pa > pb
+	}
+	ra, rb := s.stats(a).Running, s.stats(b).Running
+	if ra != rb {
+		return ra < rb
+	}
+	if s.served[a] != s.served[b] {
+		return s.served[a] < s.served[b]
+	}
+	return s.queues[a][0].seq < s.queues[b][0].seq
+}
+
+// atCap reports whether source is running as many tasks as it may
+func (s *Scheduler) atCap(source string) bool {
+	limit, ok := s.cfg.Limits[strings.ToLower(source)]
+	if !ok {
+		limit = s.cfg.PerSource
+	}
+	return limit > 0 && s.stats(source).Running >= limit
+}
+
+func (s *Scheduler) stats(source string) *SourceStats {
+	st, ok := s.sources[source]
+	if !ok {
+		st = &SourceStats{}
+		s.sources[source] = st
+	}
+	return st
+}
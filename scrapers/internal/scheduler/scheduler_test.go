@@ -0,0 +1,140 @@
+package scheduler
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gauge tracks how many tasks run at once
+type gauge struct {
+	mu       sync.Mutex
+	now, max int
+}
+
+func (g *gauge) run(d time.Duration) {
+	g.mu.Lock()
+	g.now++
+	if g.now > g.max {
+		g.max = g.now
+	}
+	g.mu.Unlock()
+
+	time.Sleep(d)
+
+	g.mu.Lock()
+	g.now--
+	g.mu.Unlock()
+}
+
+func TestWorkerCap(t *testing.T) {
+	s := New(Config{Workers: 3})
+	var all gauge
+	for i := 0; i < 6; i++ {
+		s.Submit(Task{Source: "a", Run: func() { all.run(5 * time.Millisecond) }})
+		s.Submit(Task{Source: "b", Run: func() { all.run(5 * time.Millisecond) }})
+	}
+	s.Wait()
+	s.Close()
+
+	if all.max > 3 {
+		t.Errorf("%d tasks ran at once, want at most 3", all.max)
+	}
+	stats := s.Stats()
+	if stats.Done != 12 || stats.Queued != 0 || stats.Running != 0 {
+		t.Errorf("stats = %+v, want 12 done and nothing left", stats)
+	}
+	if stats.Sources["a"].Done != 6 || stats.MaxQueued < 1 {
+		t.Errorf("source stats = %+v, max queued %d", stats.Sources["a"], stats.MaxQueued)
+	}
+}
+
+func TestPerSourceCap(t *testing.T) {
+	s := New(Config{Workers: 4, PerSource: 1, Limits: map[string]int{"wide": 2}})
+	var narrow, wide gauge
+	for i := 0; i < 4; i++ {
+		s.Submit(Task{Source: "narrow", Run: func() { narrow.run(5 * time.Millisecond) }})
+		s.Submit(Task{Source: "Wide", Run: func() { wide.run(5 * time.Millisecond) }})
+	}
+	s.Wait()
+	s.Close()
+
+	if narrow.max != 1 {
+		t.Errorf("narrow ran %d at once, want 1", narrow.max)
+	}
+	if wide.max > 2 {
+		t.Errorf("wide ran %d at once, want at most 2 (limits ignore case)", wide.max)
+	}
+}
+
+// order runs tasks on a single worker, which is held until all are queued,
+// and returns the order they started in
+func order(cfg Config, tasks []Task) []string {
+	cfg.Workers = 1
+	s := New(cfg)
+
+	release := make(chan struct{})
+	s.Submit(Task{Source: "hold", Priority: 100, Run: func() { <-release }})
+
+	var mu sync.Mutex
+	var started []string
+	for _, task := range tasks {
+		name := task.Source
+		task.Run = func() {
+			mu.Lock()
+			started = append(started, name)
+			mu.Unlock()
+		}
+		s.Submit(task)
+	}
+	close(release)
+	s.Wait()
+	s.Close()
+	return started
+}
+
+func TestPriority(t *testing.T) {
+	got := order(Config{Priorities: map[string]int{"boosted": 5}}, []Task{
+		{Source: "low"},
+		{Source: "high", Priority: 2},
+		{Source: "boosted"},
+	})
+	if want := []string{"boosted", "high", "low"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("started %v, want %v", got, want)
+	}
+}
+
+func TestFairness(t *testing.T) {
+	// A source that queued many tasks first still takes turns with the others
+	got := order(Config{}, []Task{
+		{Source: "big"}, {Source: "big"}, {Source: "big"}, {Source: "big"},
+		{Source: "small"}, {Source: "other"},
+	})
+	if want := []string{"big", "small", "other", "big", "big", "big"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("started %v, want %v", got, want)
+	}
+}
+
+func TestSourceValues(t *testing.T) {
+	got := sourceValues(" LinkedIn=1, JobPosting = 3,broken,=2,bad=x")
+	if want := map[string]int{"linkedin": 1, "jobposting": 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("SCHEDULER_WORKERS", "")
+	t.Setenv("SCHEDULER_PER_SOURCE", "")
+	t.Setenv("SCHEDULER_LIMITS", "JobPosting=2")
+	t.Setenv("SCHEDULER_PRIORITIES", "")
+	cfg := ConfigFromEnv()
+	if cfg.Workers != 4 || cfg.PerSource != 1 || cfg.Limits["jobposting"] != 2 {
+		t.Errorf("defaults = %+v, want 4 workers and 1 task per source", cfg)
+	}
+
+	t.Setenv("SCHEDULER_WORKERS", "6")
+	if cfg := ConfigFromEnv(); cfg.Workers != 6 {
+		t.Errorf("workers = %d, want 6", cfg.Workers)
+	}
+}
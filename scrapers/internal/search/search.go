@@ -0,0 +1,109 @@
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+)
+
+// Saved is a named job filter that users subscribe to
+type Saved struct {
+	Name      string   `json:"name"`
+	Skills    []string `json:"skills,omitempty"`     // all must be present in the job's tags
+	Location  string   `json:"location,omitempty"`   // case-insensitive substring
+	Remote    *bool    `json:"remote,omitempty"`     // nil matches both
+	Seniority string   `json:"seniority,omitempty"`  // one of the Seniority* levels
+	MaxAge    int      `json:"maxAgeDays,omitempty"` // only jobs posted within this many days; 0 for any
+}
+
+// Match reports whether job satisfies every constraint of the search
+func (s Saved) Match(job models.Job) bool {
+	for _, skill := range s.Skills {
+		if !hasTag(job.Tags, skill) {
+			return false
+		}
+	}
+	if s.Location != "" && !strings.Contains(strings.ToLower(job.Location), strings.ToLower(s.Location)) {
+		return false
+	}
+	if s.Remote != nil && job.Remote != *s.Remote {
+		return false
+	}
+	if s.Seniority != "" && !strings.EqualFold(Seniority(job), s.Seniority) {
+		return false
+	}
+	if s.MaxAge > 0 && job.PostedAt.Before(s.Since(time.Now())) {
+		return false
+	}
+	return true
+}
+
+// Since is the earliest posting date MaxAge allows, or zero without one
+func (s Saved) Since(now time.Time) time.Time {
+	if s.MaxAge <= 0 {
+		return time.Time{}
+	}
+	return now.AddDate(0, 0, -s.MaxAge)
+}
+
+// Filter returns the jobs matching the search, preserving order
+func (s Saved) Filter(jobs []models.Job) []models.Job {
+	var out []models.Job
+	for _, j := range jobs {
+		if s.Match(j) {
+			out = append(out, j)
+		}
+	}
+	return out
+}
+
+// Slug is a filesystem- and URL-safe form of the search name
+func (s Saved) Slug() string {
+	slug := nonSlug.ReplaceAllString(strings.ToLower(s.Name), "-")
+	return strings.Trim(slug, "-")
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Load reads saved searches from a JSON file containing an array of searches
+func Load(path string) ([]Saved, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read saved searches: %v", err)
+	}
+
+	var searches []Saved
+	if err := json.Unmarshal(data, &searches); err != nil {
+		return nil, fmt.Errorf("failed to decode saved searches: %v", err)
+	}
+	for i, s := range searches {
+		if s.Name == "" {
+			return nil, fmt.Errorf("saved search #%d has no name", i+1)
+		}
+	}
+	return searches, nil
+}
+
+// Find returns the saved search whose name or slug matches name
+func Find(searches []Saved, name string) (Saved, bool) {
+	for _, s := range searches {
+		if strings.EqualFold(s.Name, name) || s.Slug() == name {
+			return s, true
+		}
+	}
+	return Saved{}, false
+}
+
+func hasTag(tags []string, want string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, want) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package search
+
+import (
+	"regexp"
+
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+)
+
+// Seniority levels inferred from job titles
+const (
+	SeniorityIntern = "intern"
+	SeniorityJunior = "junior"
+	SeniorityMid    = "mid"
+	SenioritySenior = "senior"
+	SeniorityLead   = "lead"
+)
+
+// Checked in order, so "Senior Staff Engineer" is lead and "Junior Intern" is intern
+var seniorityPatterns = []struct {
+	level string
+	re    *regexp.Regexp
+}{
+	{SeniorityIntern, regexp.MustCompile(`(?i)\b(intern|internship|trainee)\b`)},
+	{SeniorityLead, regexp.MustCompile(`(?i)\b(lead|staff|principal|architect|head of|director|vp)\b`)},
+	{SenioritySenior, regexp.MustCompile(`(?i)\b(senior|sr\.?|iii|iv)\b`)},
+	{SeniorityJunior, regexp.MustCompile(`(?i)\b(junior|jr\.?|entry[- ]level|fresher|graduate|new grad)\b`)},
+}
+
+// Seniority guesses the level of a job from its title and tags, defaulting to mid
+func Seniority(job models.Job) string {
+	for _, p := range seniorityPatterns {
+		if p.re.MatchString(job.Title) {
+			return p.level
+		}
+	}
+	// Freshersworld only lists fresher roles
+	if job.Source == "Freshersworld" {
+		return SeniorityJunior
+	}
+	return SeniorityMid
+}
@@ -0,0 +1,220 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+)
+
+// Filter narrows a job query. Zero values mean "no constraint".
+type Filter struct {
+	Skill     string    // matches a tag, case-insensitive
+	Skills    []string  // every one must match a tag, case-insensitive
+	Source    string    // exact source name, case-insensitive
+	CompanyID string    // exact company.Directory ID
+	Location  string    // substring of the location, case-insensitive
+	Remote    *bool     // nil matches both
+	MinSalary int       // upper end of the parsed salary range must reach this
+	Since     time.Time // posted at or after
+	Until     time.Time // posted before
+	SeenSince time.Time // first scraped at or after, i.e. new to us
+	Sort      string    // one of the SortFields keys, prefixed with "-" for descending
+	Limit     int
+	Offset    int
+}
+
+// SortFields maps the public sort keys to their columns
+var SortFields = map[string]string{
+	"posted":  "posted_at",
+	"scraped": "scraped_at",
+	"company": "company COLLATE NOCASE",
+	"title":   "title COLLATE NOCASE",
+	"salary":  "salary_max",
+}
+
+// Count is one distinct value and how many jobs carry it
+type Count struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+const jobColumns = `id, title, company, location, description, url, source,
+	posted_at, scraped_at, remote, salary, tags, department, company_id`
+
+// Query returns jobs matching f, newest postings first
+func (s *Store) Query(f Filter) ([]models.Job, error) {
+	where, args := f.clauses()
+
+	q := `SELECT ` + jobColumns + ` FROM jobs`
+	if len(where) > 0 {
+		q += ` WHERE ` + strings.Join(where, ` AND `)
+	}
+	q += ` ORDER BY ` + f.orderBy()
+	if f.Limit > 0 {
+		q += fmt.Sprintf(` LIMIT %d OFFSET %d`, f.Limit, f.Offset)
+	}
+
+	rows, err := s.db.Query(q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query jobs: %v", err)
+	}
+	defer rows.Close()
+
+	var jobs []models.Job
+	for rows.Next() {
+		j, err := scanJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read job: %v", err)
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs, rows.Err()
+}
+
+// Count returns how many jobs match f, ignoring its limit and offset
+func (s *Store) Count(f Filter) (int, error) {
+	where, args := f.clauses()
+
+	q := `SELECT COUNT(*) FROM jobs`
+	if len(where) > 0 {
+		q += ` WHERE ` + strings.Join(where, ` AND `)
+	}
+
+	var n int
+	if err := s.db.QueryRow(q, args...).Scan(&n); err != nil {
+		return 0, fmt.Errorf("failed to count jobs: %v", err)
+	}
+	return n, nil
+}
+
+// Get returns a single job by ID; found is false when it isn't stored
+func (s *Store) Get(id string) (job models.Job, found bool, err error) {
+	row := s.db.QueryRow(`SELECT `+jobColumns+` FROM jobs WHERE id = ?`, id)
+	job, err = scanJob(row)
+	if err == sql.ErrNoRows {
+		return job, false, nil
+	}
+	if err != nil {
+		return job, false, fmt.Errorf("failed to read job: %v", err)
+	}
+	return job, true, nil
+}
+
+// Sources lists every source with its job count
+func (s *Store) Sources() ([]Count, error) {
+	return s.counts(`SELECT source, COUNT(*) FROM jobs GROUP BY source ORDER BY COUNT(*) DESC, source`)
+}
+
+// Skills lists every tag with the number of jobs carrying it
+func (s *Store) Skills() ([]Count, error) {
+	return s.counts(`SELECT tag, COUNT(*) FROM job_tags GROUP BY tag ORDER BY COUNT(*) DESC, tag`)
+}
+
+// Ping checks that the database is reachable
+func (s *Store) Ping() error {
+	return s.db.Ping()
+}
+
+func (s *Store) counts(q string) ([]Count, error) {
+	rows, err := s.db.Query(q)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query counts: %v", err)
+	}
+	defer rows.Close()
+
+	counts := []Count{}
+	for rows.Next() {
+		var c Count
+		if err := rows.Scan(&c.Name, &c.Count); err != nil {
+			return nil, fmt.Errorf("failed to read count: %v", err)
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
+
+// orderBy builds the ORDER BY clause; unknown sort keys fall back to newest first
+func (f Filter) orderBy() string {
+	key, dir := strings.TrimPrefix(f.Sort, "-"), "ASC"
+	if strings.HasPrefix(f.Sort, "-") {
+		dir = "DESC"
+	}
+	col, ok := SortFields[key]
+	if !ok {
+		return `posted_at DESC, id`
+	}
+	return col + ` ` + dir + `, id`
+}
+
+func (f Filter) clauses() ([]string, []interface{}) {
+	var where []string
+	var args []interface{}
+
+	if f.Skill != "" {
+		where = append(where, `EXISTS (SELECT 1 FROM job_tags t WHERE t.job_id = jobs.id AND t.tag = ?)`)
+		args = append(args, f.Skill)
+	}
+	for _, skill := range f.Skills {
+		where = append(where, `EXISTS (SELECT 1 FROM job_tags t WHERE t.job_id = jobs.id AND t.tag = ?)`)
+		args = append(args, skill)
+	}
+	if f.Source != "" {
+		where = append(where, `source = ? COLLATE NOCASE`)
+		args = append(args, f.Source)
+	}
+	if f.CompanyID != "" {
+		where = append(where, `company_id = ?`)
+		args = append(args, f.CompanyID)
+	}
+	if f.Location != "" {
+		where = append(where, `location LIKE ?`)
+		args = append(args, "%"+f.Location+"%")
+	}
+	if f.Remote != nil {
+		where = append(where, `remote = ?`)
+		args = append(args, *f.Remote)
+	}
+	if f.MinSalary > 0 {
+		where = append(where, `salary_max >= ?`)
+		args = append(args, f.MinSalary)
+	}
+	if !f.Since.IsZero() {
+		where = append(where, `posted_at >= ?`)
+		args = append(args, f.Since.Unix())
+	}
+	if !f.Until.IsZero() {
+		where = append(where, `posted_at < ?`)
+		args = append(args, f.Until.Unix())
+	}
+	if !f.SeenSince.IsZero() {
+		where = append(where, `first_seen >= ?`)
+		args = append(args, f.SeenSince.Unix())
+	}
+	return where, args
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanJob(row rowScanner) (models.Job, error) {
+	var j models.Job
+	var postedAt, scrapedAt int64
+	var tags string
+
+	err := row.Scan(&j.ID, &j.Title, &j.Company, &j.Location, &j.Description, &j.URL, &j.Source,
+		&postedAt, &scrapedAt, &j.Remote, &j.Salary, &tags, &j.Department, &j.CompanyID)
+	if err != nil {
+		return j, err
+	}
+	j.PostedAt = time.Unix(postedAt, 0)
+	j.ScrapedAt = time.Unix(scrapedAt, 0)
+	if err := json.Unmarshal([]byte(tags), &j.Tags); err != nil {
+		return j, err
+	}
+	return j, nil
+}
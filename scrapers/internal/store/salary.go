@@ -0,0 +1,55 @@
+package store
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// salaryNumber matches amounts like "120K", "$150,000", "₹8L" or "1.5M", and
+// percentages so that equity or bonus shares can be told apart and skipped
+var salaryNumber = regexp.MustCompile(`(?i)(\d+(?:[.,]\d+)*)\s*(k|lpa|lakhs?|l|m)?\b(\s*%)?`)
+
+// parseSalary pulls the lowest and highest amounts out of a free-form salary string.
+// Currency is ignored; both values are 0 when nothing parseable is present.
+func parseSalary(s string) (min, max int) {
+	var amounts []float64
+	var units []float64
+
+	for _, m := range salaryNumber.FindAllStringSubmatch(s, -1) {
+		if m[3] != "" {
+			continue
+		}
+		n, err := strconv.ParseFloat(strings.ReplaceAll(m[1], ",", ""), 64)
+		if err != nil {
+			continue
+		}
+		unit := 0.0
+		switch strings.ToLower(m[2]) {
+		case "k":
+			unit = 1_000
+		case "l", "lpa", "lakh", "lakhs":
+			unit = 100_000
+		case "m":
+			unit = 1_000_000
+		}
+		amounts = append(amounts, n)
+		units = append(units, unit)
+	}
+
+	// In ranges like "5-8 LPA" the unit is only written once, after the last number
+	unit := 1.0
+	for i := len(amounts) - 1; i >= 0; i-- {
+		if units[i] != 0 {
+			unit = units[i]
+		}
+		v := int(amounts[i] * unit)
+		if min == 0 || v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+	return min, max
+}
@@ -0,0 +1,254 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+	_ "modernc.org/sqlite"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS jobs (
+	id          TEXT PRIMARY KEY,
+	title       TEXT NOT NULL,
+	company     TEXT NOT NULL,
+	location    TEXT NOT NULL,
+	description TEXT NOT NULL,
+	url         TEXT NOT NULL,
+	source      TEXT NOT NULL,
+	posted_at   INTEGER NOT NULL,
+	scraped_at  INTEGER NOT NULL,
+	remote      INTEGER NOT NULL,
+	salary      TEXT NOT NULL,
+	department  TEXT NOT NULL DEFAULT '',
+	company_id  TEXT NOT NULL DEFAULT '',
+	salary_min  INTEGER NOT NULL,
+	salary_max  INTEGER NOT NULL,
+	tags        TEXT NOT NULL,
+	hash        TEXT NOT NULL,
+	first_seen  INTEGER NOT NULL,
+	last_seen   INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_jobs_source ON jobs(source);
+CREATE INDEX IF NOT EXISTS idx_jobs_posted_at ON jobs(posted_at);
+
+CREATE TABLE IF NOT EXISTS job_tags (
+	job_id TEXT NOT NULL,
+	tag    TEXT NOT NULL COLLATE NOCASE,
+	PRIMARY KEY (job_id, tag)
+);
+CREATE INDEX IF NOT EXISTS idx_job_tags_tag ON job_tags(tag);
+
+CREATE TABLE IF NOT EXISTS job_history (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	job_id      TEXT NOT NULL,
+	hash        TEXT NOT NULL,
+	data        TEXT NOT NULL,
+	recorded_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_job_history_job ON job_history(job_id);
+`
+
+// columns added after the first release; Open adds any that an older database lacks
+var columns = []struct{ table, name, ddl string }{
+	{"jobs", "department", `ALTER TABLE jobs ADD COLUMN department TEXT NOT NULL DEFAULT ''`},
+	{"jobs", "company_id", `ALTER TABLE jobs ADD COLUMN company_id TEXT NOT NULL DEFAULT ''`},
+}
+
+// Store is an embedded SQLite database of every job the scraper has seen
+type Store struct {
+	db *sql.DB
+}
+
+// HistoryEntry is one recorded version of a job
+type HistoryEntry struct {
+	Job        models.Job `json:"job"`
+	Hash       string     `json:"hash"`
+	RecordedAt time.Time  `json:"recordedAt"`
+}
+
+// Open opens (or creates) the store at path and applies the schema
+func Open(path string) (*Store, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create store dir: %v", err)
+		}
+	}
+
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store: %v", err)
+	}
+	// SQLite allows a single writer; serialising here avoids SQLITE_BUSY between our own goroutines
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to apply store schema: %v", err)
+	}
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate store: %v", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// OpenReadOnly opens an existing store for reading only, for commands that
+// serve or report on what the scraper saved. Unlike Open it creates nothing: a
+// missing store, or one a newer scraper still has to migrate, is an error.
+func OpenReadOnly(path string) (*Store, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to open store: %v", err)
+	}
+
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store: %v", err)
+	}
+	for _, c := range columns {
+		var n int
+		err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, c.table, c.name).Scan(&n)
+		if err == nil && n == 0 {
+			err = fmt.Errorf("%s.%s is missing; run the scraper once to migrate it", c.table, c.name)
+		}
+		if err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to open store: %v", err)
+		}
+	}
+	return &Store{db: db}, nil
+}
+
+// migrate adds columns that are missing from databases created by older versions
+func migrate(db *sql.DB) error {
+	for _, c := range columns {
+		var n int
+		err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, c.table, c.name).Scan(&n)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			if _, err := db.Exec(c.ddl); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Close releases the underlying database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// SaveJobs upserts jobs and appends a history entry whenever a job's content changes
+func (s *Store) SaveJobs(jobs []models.Job) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	for _, j := range jobs {
+		if j.ID == "" {
+			continue
+		}
+		if err := saveJob(tx, j, now); err != nil {
+			return fmt.Errorf("failed to save job %s: %v", j.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit jobs: %v", err)
+	}
+	return nil
+}
+
+func saveJob(tx *sql.Tx, j models.Job, now time.Time) error {
+	hash := j.ContentHash()
+
+	var prevHash string
+	err := tx.QueryRow(`SELECT hash FROM jobs WHERE id = ?`, j.ID).Scan(&prevHash)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	isNew := err == sql.ErrNoRows
+
+	tags, err := json.Marshal(j.Tags)
+	if err != nil {
+		return err
+	}
+	salaryMin, salaryMax := parseSalary(j.Salary)
+
+	_, err = tx.Exec(`
+		INSERT INTO jobs (id, title, company, location, description, url, source,
+			posted_at, scraped_at, remote, salary, salary_min, salary_max, tags, department, company_id, hash, first_seen, last_seen)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			title = excluded.title, company = excluded.company, location = excluded.location,
+			description = excluded.description, url = excluded.url, source = excluded.source,
+			posted_at = excluded.posted_at, scraped_at = excluded.scraped_at, remote = excluded.remote,
+			salary = excluded.salary, salary_min = excluded.salary_min, salary_max = excluded.salary_max,
+			tags = excluded.tags, department = excluded.department,
+			company_id = excluded.company_id, hash = excluded.hash, last_seen = excluded.last_seen`,
+		j.ID, j.Title, j.Company, j.Location, j.Description, j.URL, j.Source,
+		j.PostedAt.Unix(), j.ScrapedAt.Unix(), j.Remote, j.Salary, salaryMin, salaryMax,
+		string(tags), j.Department, j.CompanyID, hash, now.Unix(), now.Unix(),
+	)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM job_tags WHERE job_id = ?`, j.ID); err != nil {
+		return err
+	}
+	for _, tag := range j.Tags {
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO job_tags (job_id, tag) VALUES (?, ?)`, j.ID, tag); err != nil {
+			return err
+		}
+	}
+
+	if isNew || prevHash != hash {
+		data, err := json.Marshal(j)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`INSERT INTO job_history (job_id, hash, data, recorded_at) VALUES (?, ?, ?, ?)`,
+			j.ID, hash, string(data), now.Unix())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// History returns every recorded version of a job, oldest first
+func (s *Store) History(id string) ([]HistoryEntry, error) {
+	rows, err := s.db.Query(`SELECT hash, data, recorded_at FROM job_history WHERE job_id = ? ORDER BY id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query history: %v", err)
+	}
+	defer rows.Close()
+
+	var entries []HistoryEntry
+	for rows.Next() {
+		var e HistoryEntry
+		var data string
+		var recordedAt int64
+		if err := rows.Scan(&e.Hash, &data, &recordedAt); err != nil {
+			return nil, fmt.Errorf("failed to read history: %v", err)
+		}
+		if err := json.Unmarshal([]byte(data), &e.Job); err != nil {
+			return nil, fmt.Errorf("failed to decode history: %v", err)
+		}
+		e.RecordedAt = time.Unix(recordedAt, 0)
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
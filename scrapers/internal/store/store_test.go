@@ -0,0 +1,230 @@
+package store
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/groot34/job-aggregator/scraper/internal/models"
+)
+
+func ids(jobs []models.Job) []string {
+	var out []string
+	for _, j := range jobs {
+		out = append(out, j.ID)
+	}
+	return out
+}
+
+func openTemp(t *testing.T) *Store {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "nested", "jobs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestOpenCreatesStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "jobs.db")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("store file not created: %v", err)
+	}
+	if err := s.Ping(); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	// Reopening an existing store applies the schema again without complaint
+	s, err = Open(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	s.Close()
+}
+
+func TestOpenMigratesOldStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The jobs table as the first release created it, before department and company_id
+	_, err = db.Exec(`CREATE TABLE jobs (
+		id TEXT PRIMARY KEY, title TEXT NOT NULL, company TEXT NOT NULL, location TEXT NOT NULL,
+		description TEXT NOT NULL, url TEXT NOT NULL, source TEXT NOT NULL,
+		posted_at INTEGER NOT NULL, scraped_at INTEGER NOT NULL, remote INTEGER NOT NULL,
+		salary TEXT NOT NULL, salary_min INTEGER NOT NULL, salary_max INTEGER NOT NULL,
+		tags TEXT NOT NULL, hash TEXT NOT NULL, first_seen INTEGER NOT NULL, last_seen INTEGER NOT NULL);
+		INSERT INTO jobs VALUES ('old-1', 'Go Engineer', 'Acme', 'Berlin', '', '', 'Old', 0, 0, 0, '', 0, 0, '["Go"]', '', 0, 0);`)
+	db.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Readers can't migrate, so they refuse the old schema rather than fail per query
+	if ro, err := OpenReadOnly(path); err == nil {
+		ro.Close()
+		t.Error("OpenReadOnly accepted a store that still needs migrating")
+	}
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	job, found, err := s.Get("old-1")
+	if err != nil || !found {
+		t.Fatalf("Get after migration = %v, %v", found, err)
+	}
+	if job.Department != "" || job.CompanyID != "" || job.Title != "Go Engineer" {
+		t.Errorf("migrated job = %+v", job)
+	}
+	if err := s.SaveJobs([]models.Job{{ID: "new-1", Title: "SRE", Department: "Infra", CompanyID: "acme"}}); err != nil {
+		t.Errorf("saving into migrated store: %v", err)
+	}
+}
+
+func TestOpenReadOnly(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := OpenReadOnly(filepath.Join(dir, "missing.db")); err == nil {
+		t.Error("opened a store that doesn't exist")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "missing.db")); !os.IsNotExist(err) {
+		t.Errorf("OpenReadOnly created the store: %v", err)
+	}
+
+	path := filepath.Join(dir, "jobs.db")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveJobs([]models.Job{{ID: "a", Title: "Go Engineer"}}); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	ro, err := OpenReadOnly(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ro.Close()
+	if _, found, err := ro.Get("a"); err != nil || !found {
+		t.Errorf("Get = %v, %v", found, err)
+	}
+	if err := ro.SaveJobs([]models.Job{{ID: "b", Title: "SRE"}}); err == nil {
+		t.Error("SaveJobs succeeded on a read-only store")
+	}
+}
+
+func TestQuery(t *testing.T) {
+	s := openTemp(t)
+	day := func(n int) time.Time { return time.Date(2024, 3, n, 12, 0, 0, 0, time.UTC) }
+	err := s.SaveJobs([]models.Job{
+		{ID: "a", Title: "Go Engineer", Company: "acme", Location: "Berlin, DE", Source: "Lever", PostedAt: day(1), Salary: "€70K - €90K", Tags: []string{"Go", "Postgres"}},
+		{ID: "b", Title: "Backend Developer", Company: "Globex", Location: "Remote", Source: "Greenhouse", PostedAt: day(3), Remote: true, Salary: "$120K - $150K", Tags: []string{"Go"}},
+		{ID: "c", Title: "Data Engineer", Company: "Initech", Location: "Pune", Source: "LinkedIn", PostedAt: day(2), Salary: "₹8L - ₹12L", Tags: []string{"Python"}},
+		{ID: "d", Title: "Designer", Company: "Hooli", Location: "Berlin", Source: "lever", PostedAt: day(4), CompanyID: "hooli"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	remote := true
+	for _, tc := range []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{"newest first by default", Filter{}, []string{"d", "b", "c", "a"}},
+		{"skill is case-insensitive", Filter{Skill: "go"}, []string{"b", "a"}},
+		{"every skill must match", Filter{Skills: []string{"go", "postgres"}}, []string{"a"}},
+		{"source ignores case", Filter{Source: "LEVER"}, []string{"d", "a"}},
+		{"company ID", Filter{CompanyID: "hooli"}, []string{"d"}},
+		{"location substring", Filter{Location: "berlin"}, []string{"d", "a"}},
+		{"remote", Filter{Remote: &remote}, []string{"b"}},
+		{"min salary reaches the top of the range", Filter{MinSalary: 1_000_000}, []string{"c"}},
+		{"posted window", Filter{Since: day(2), Until: day(4)}, []string{"b", "c"}},
+		{"sort by company", Filter{Sort: "company"}, []string{"a", "b", "d", "c"}},
+		{"sort by salary descending", Filter{Sort: "-salary"}, []string{"c", "b", "a", "d"}},
+		{"unknown sort falls back", Filter{Sort: "bogus"}, []string{"d", "b", "c", "a"}},
+		{"pagination", Filter{Limit: 2, Offset: 1}, []string{"b", "c"}},
+	} {
+		jobs, err := s.Query(tc.filter)
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+		if got := ids(jobs); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+		// Count ignores the page but otherwise agrees with Query
+		if tc.filter.Limit == 0 {
+			if n, err := s.Count(tc.filter); err != nil || n != len(tc.want) {
+				t.Errorf("%s: Count = %d, %v; want %d", tc.name, n, err, len(tc.want))
+			}
+		}
+	}
+
+	skills, err := s.Skills()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Count{{"Go", 2}, {"Postgres", 1}, {"Python", 1}}; !reflect.DeepEqual(skills, want) {
+		t.Errorf("Skills = %v, want %v", skills, want)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	s := openTemp(t)
+	job := models.Job{ID: "a", Title: "Go Engineer", Company: "Acme", Salary: "$100K"}
+
+	// Saving unchanged content again records nothing; a changed salary does
+	for _, salary := range []string{"$100K", "$100K", "$110K"} {
+		job.Salary = salary
+		if err := s.SaveJobs([]models.Job{job}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, err := s.History("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Job.Salary != "$100K" || entries[1].Job.Salary != "$110K" {
+		t.Errorf("history = %+v, want the $100K and $110K versions", entries)
+	}
+	if entries, err := s.History("missing"); err != nil || len(entries) != 0 {
+		t.Errorf("History(missing) = %v, %v", entries, err)
+	}
+}
+
+func TestParseSalary(t *testing.T) {
+	for _, tc := range []struct {
+		in       string
+		min, max int
+	}{
+		{"$120K - $150K", 120_000, 150_000},
+		{"$150,000", 150_000, 150_000},
+		{"₹8L - ₹12L", 800_000, 1_200_000},
+		{"5-8 LPA", 500_000, 800_000},
+		{"12 lakhs", 1_200_000, 1_200_000},
+		{"€1.5M", 1_500_000, 1_500_000},
+		{"$35 /hour", 35, 35},
+		{"$120K + 0.5% equity", 120_000, 120_000},
+		{"$90K - $110K, 10 % bonus", 90_000, 110_000},
+		{"Competitive", 0, 0},
+		{"", 0, 0},
+	} {
+		if min, max := parseSalary(tc.in); min != tc.min || max != tc.max {
+			t.Errorf("parseSalary(%q) = %d, %d; want %d, %d", tc.in, min, max, tc.min, tc.max)
+		}
+	}
+}